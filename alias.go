@@ -0,0 +1,148 @@
+package staticmessages
+
+import "gopkg.in/yaml.v3"
+
+// mappingPair is a key and value of a mapping node, merged is set for pairs that are part of a merge key.
+type mappingPair struct {
+	key, value *yaml.Node
+	merged     bool
+}
+
+// mappingPairs returns the pairs of mapping with the merge keys replaced by the pairs of the merged mappings.
+// The merged pairs are inserted at the position of the merge key, keys of mapping itself take precedence
+// over merged keys and earlier merged mappings take precedence over later ones.
+//
+//	NotFound: &notFound
+//	  default: Not found
+//	  nl: Niet gevonden
+//	Gone:
+//	  <<: *notFound
+//	  default: Gone
+func mappingPairs(mapping *yaml.Node) []mappingPair {
+	explicit := make(map[string]bool)
+	for i := 0; (i + 1) < len(mapping.Content); i += 2 {
+		if !isMergeKey(mapping.Content[i]) {
+			explicit[mapping.Content[i].Value] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	pairs := make([]mappingPair, 0, len(mapping.Content)/2)
+
+	for i := 0; (i + 1) < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		value := mapping.Content[i+1]
+
+		if !isMergeKey(key) {
+			pairs = append(pairs, mappingPair{key: key, value: value})
+			continue
+		}
+
+		// Invalid merge keys are reported by the parser, see parser.checkMerges.
+		for _, source := range mergeSources(value) {
+			for _, pair := range mappingPairs(source) {
+				if explicit[pair.key.Value] || seen[pair.key.Value] {
+					continue
+				}
+
+				seen[pair.key.Value] = true
+				pairs = append(pairs, mappingPair{key: pair.key, value: pair.value, merged: true})
+			}
+		}
+	}
+
+	return pairs
+}
+
+// mergeSources returns the mappings of the value of a merge key, nil is returned if value isn't a mapping or a sequence of mappings.
+func mergeSources(value *yaml.Node) []*yaml.Node {
+	value = deref(value)
+
+	switch value.Kind {
+	case yaml.MappingNode:
+		return []*yaml.Node{value}
+	case yaml.SequenceNode:
+		sources := make([]*yaml.Node, 0, len(value.Content))
+		for _, item := range value.Content {
+			if item = deref(item); item.Kind != yaml.MappingNode {
+				return nil
+			}
+
+			sources = append(sources, item)
+		}
+
+		return sources
+	}
+
+	return nil
+}
+
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge"
+}
+
+// deref returns the anchored node of an alias, other nodes are returned as is.
+func deref(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// resolve returns a copy of node without aliases and merge keys, see mappingPairs for the handling of merge keys.
+// Aliases are replaced by the anchored node so errors point at the anchor.
+func resolve(node *yaml.Node) *yaml.Node {
+	node = deref(node)
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		resolved := *node
+		resolved.Content = make([]*yaml.Node, 0, len(node.Content))
+		for _, pair := range mappingPairs(node) {
+			resolved.Content = append(resolved.Content, resolve(pair.key), resolve(pair.value))
+		}
+
+		return &resolved
+	case yaml.DocumentNode, yaml.SequenceNode:
+		resolved := *node
+		resolved.Content = make([]*yaml.Node, 0, len(node.Content))
+		for _, item := range node.Content {
+			resolved.Content = append(resolved.Content, resolve(item))
+		}
+
+		return &resolved
+	}
+
+	return node
+}
+
+// checkMerges reports merge keys in node that don't merge a mapping or a sequence of mappings.
+func (p *parser) checkMerges(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; (i + 1) < len(node.Content); i += 2 {
+			if isMergeKey(node.Content[i]) && mergeSources(node.Content[i+1]) == nil {
+				p.errorf(node.Content[i+1], "", "", "%w: << must merge a mapping or a sequence of mappings", ErrYamlDefinitionInvalid)
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		p.checkMerges(child)
+	}
+}
+
+// clearMergeTags removes the !!merge tag from the merge keys in node, the encoder writes the tag otherwise.
+func clearMergeTags(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			if isMergeKey(node.Content[i]) {
+				node.Content[i].Tag = ""
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		clearMergeTags(child)
+	}
+}
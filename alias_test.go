@@ -0,0 +1,122 @@
+package staticmessages_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestParseAliases(t *testing.T) {
+	t.Run("aliases and merge keys", func(t *testing.T) {
+		container, err := staticmessages.Parse("errors", strings.NewReader(`_shared:
+  translations: &translations
+    nl: Er ging iets mis
+    de: Etwas ist schiefgelaufen
+Internal:
+  <<: *translations
+  default: Something went wrong
+Unavailable:
+  default: Service unavailable
+  <<: *translations
+  de: Dienst nicht verfügbar
+Timeout: &timeout
+  default: Timeout
+  nl: Time-out
+Deadline: *timeout
+`))
+		require.NoError(t, err)
+		require.Len(t, container.Messages, 4)
+
+		internal := container.Messages[0]
+		require.Equal(t, "Internal", internal.Identifier)
+		require.Equal(t, "Something went wrong", internal.Default.Message)
+		require.Equal(t, "Er ging iets mis", internal.Translation("nl").Message)
+
+		// Keys of the message take precedence over merged keys.
+		unavailable := container.Messages[1]
+		require.Equal(t, "Dienst nicht verfügbar", unavailable.Translation("de").Message)
+		require.Equal(t, "Er ging iets mis", unavailable.Translation("nl").Message)
+
+		deadline := container.Messages[3]
+		require.Equal(t, "Deadline", deadline.Identifier)
+		require.Equal(t, "Time-out", deadline.Translation("nl").Message)
+	})
+
+	t.Run("merged groups keep the order", func(t *testing.T) {
+		container, err := staticmessages.Parse("errors", strings.NewReader(`Common: &common
+  NotFound:
+    default: Not found
+  Forbidden:
+    default: Forbidden
+User:
+  Deleted:
+    default: User deleted
+  <<: *common
+  Forbidden:
+    default: User forbidden
+`))
+		require.NoError(t, err)
+
+		identifiers := make([]string, 0)
+		for _, l := range container.Messages {
+			identifiers = append(identifiers, l.Identifier)
+		}
+
+		require.Equal(t, []string{"CommonNotFound", "CommonForbidden", "UserDeleted", "UserNotFound", "UserForbidden"}, identifiers)
+		require.Equal(t, "User forbidden", container.Messages[4].Default.Message)
+	})
+
+	t.Run("errors point at the anchor", func(t *testing.T) {
+		_, err := staticmessages.Parse("errors", strings.NewReader(`_shared:
+  translations: &translations
+    nl: Gebruiker %(ID)s niet gevonden
+NotFound:
+  default: User %(ID)d not found
+  <<: *translations
+`))
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 1)
+		require.Equal(t, "NotFound", parseErrs[0].Identifier)
+		require.Equal(t, 3, parseErrs[0].Line)
+	})
+
+	t.Run("invalid merge", func(t *testing.T) {
+		_, err := staticmessages.Parse("errors", strings.NewReader(`NotFound:
+  default: Not found
+  <<: nl
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+}
+
+func TestImportUnitsAliases(t *testing.T) {
+	src := `_shared:
+  translations: &translations
+    nl: Er ging iets mis
+Internal: &internal
+  default: Something went wrong
+  <<: *translations
+Unavailable: *internal
+`
+
+	updated, err := staticmessages.ImportUnits("errors.yml", []byte(src), "de", []*staticmessages.Unit{
+		{ID: "Unavailable", Target: "Dienst nicht verfügbar"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, `_shared:
+  translations: &translations
+    nl: Er ging iets mis
+Internal: &internal
+  default: Something went wrong
+  <<: *translations
+Unavailable:
+  <<: *internal
+  de: Dienst nicht verfügbar
+`, string(updated))
+}
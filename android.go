@@ -0,0 +1,105 @@
+package staticmessages
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// WriteAndroid writes the messages of locale as an Android string resources file, an empty locale writes the default messages.
+// Placeholders are positional like %1$s so translations can change the order. Plurals are written as plurals resources with the
+// categories of the locale, the default messages use the categories of sourceLocale. Exact variants like =0 are left out.
+// Selects are written as a string per variant.
+func WriteAndroid(msg *Messages, sourceLocale, locale string, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	bw.WriteString("<!-- Code generated by \"msggen\"; DO NOT EDIT. -->\n")
+	bw.WriteString("<resources>\n")
+
+	categoryLocale := locale
+	if locale == "" {
+		categoryLocale = sourceLocale
+	}
+
+	for _, r := range mobileResources(msg) {
+		m := r.message(locale)
+		if m == nil {
+			continue
+		}
+
+		vars := r.l.UniqueVars()
+
+		if text := docText(r.l); text != "" {
+			bw.WriteString("    <!-- " + strings.ReplaceAll(text, "--", "- -") + " -->\n")
+		}
+
+		if !r.plural {
+			bw.WriteString("    <string name=\"" + r.name + "\">" + androidEscape(positionalFormat(m, vars, androidConversion)) + "</string>\n")
+			continue
+		}
+
+		bw.WriteString("    <plurals name=\"" + r.name + "\">\n")
+
+		categories, messages := pluralQuantities(m, categoryLocale)
+		for i, category := range categories {
+			bw.WriteString("        <item quantity=\"" + category + "\">" + androidEscape(positionalFormat(messages[i], vars, androidConversion)) + "</item>\n")
+		}
+
+		bw.WriteString("    </plurals>\n")
+	}
+
+	bw.WriteString("</resources>\n")
+
+	return bw.Flush()
+}
+
+// AndroidResourceDir returns the resource directory of locale, values-pt-rBR for pt-BR. An empty locale returns values.
+func AndroidResourceDir(locale string) string {
+	if locale == "" {
+		return "values"
+	}
+
+	language, region, ok := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	if !ok {
+		return "values-" + language
+	}
+
+	return "values-" + language + "-r" + region
+}
+
+func androidConversion(v *Var) string {
+	switch v.Type {
+	case VarTypeInt:
+		return "d"
+	case VarTypeFloat:
+		return "f"
+	case VarTypeBool:
+		return "b"
+	}
+
+	return "s"
+}
+
+// androidEscaper escapes the characters that have a meaning in XML or in Android string resources.
+var androidEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`&`, "&amp;",
+	`<`, "&lt;",
+	`>`, "&gt;",
+	`'`, `\'`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\t", `\t`,
+)
+
+func androidEscape(text string) string {
+	text = androidEscaper.Replace(text)
+
+	// A leading @ or ? is read as a reference to another resource.
+	if strings.HasPrefix(text, "@") || strings.HasPrefix(text, "?") {
+		text = `\` + text
+	}
+
+	return text
+}
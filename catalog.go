@@ -0,0 +1,274 @@
+package staticmessages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownMessage = errors.New("message is unknown")
+	ErrArgInvalid     = errors.New("arg is invalid")
+)
+
+// Catalog contains generated messages by identifier, so messages can be rendered by an identifier that is only known at runtime.
+// A generated package registers its messages in its Catalog var, the identifier is the name of the generated function.
+type Catalog struct {
+	mu      sync.RWMutex
+	entries map[string]*CatalogEntry
+}
+
+// CatalogEntry is a message in a Catalog.
+type CatalogEntry struct {
+	Identifier string
+	Vars       []*Var
+	// Render renders the message with the args in the order of Vars, the args are checked and converted by the Catalog.
+	Render func(ctx context.Context, args []any) string
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{entries: make(map[string]*CatalogEntry)}
+}
+
+// Register adds the entries to the catalog, it panics when an identifier is already registered.
+func (c *Catalog) Register(entries ...*CatalogEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, entry := range entries {
+		if _, ok := c.entries[entry.Identifier]; ok {
+			panic("staticmessages: message " + entry.Identifier + " is registered twice")
+		}
+
+		c.entries[entry.Identifier] = entry
+	}
+}
+
+// Lookup returns the entry of identifier, nil is returned when the identifier isn't registered.
+func (c *Catalog) Lookup(identifier string) *CatalogEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.entries[identifier]
+}
+
+// Identifiers returns the registered identifiers in alphabetical order.
+func (c *Catalog) Identifiers() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	identifiers := make([]string, 0, len(c.entries))
+	for identifier := range c.entries {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+
+	return identifiers
+}
+
+// Render renders the message identifier in the locale of ctx. Every var of the message must be in args with a value that fits
+// the type of the var: ints accept every integer type, floats every integer and float type and strings every string type.
+// Vars with a declared Go type accept the declared type and builtin declared types accept the values of the var type as well.
+func (c *Catalog) Render(ctx context.Context, identifier string, args map[string]any) (string, error) {
+	entry := c.Lookup(identifier)
+	if entry == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownMessage, identifier)
+	}
+
+	values, err := entry.args(args)
+	if err != nil {
+		return "", err
+	}
+
+	return entry.Render(ctx, values), nil
+}
+
+// args checks args against the vars of the entry and returns them in the order of the vars.
+func (e *CatalogEntry) args(args map[string]any) ([]any, error) {
+	errs := make([]error, 0)
+	values := make([]any, len(e.Vars))
+	names := make(map[string]bool, len(e.Vars))
+
+	for i, v := range e.Vars {
+		names[v.Name] = true
+
+		arg, ok := args[v.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s of %s is missing", ErrArgInvalid, v.Name, e.Identifier))
+			continue
+		}
+
+		value, ok := v.convert(arg)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s of %s must be of type %s, got %T", ErrArgInvalid, v.Name, e.Identifier, v.typeName(), arg))
+			continue
+		}
+
+		values[i] = value
+	}
+
+	for name := range args {
+		if !names[name] {
+			errs = append(errs, fmt.Errorf("%w: %s doesn't have a var %s", ErrArgInvalid, e.Identifier, name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return values, nil
+}
+
+// typeName returns the declared Go type of v or the var type.
+func (v *Var) typeName() string {
+	if v.GoType != "" {
+		return v.GoType
+	}
+
+	return string(v.Type)
+}
+
+// builtinReflectTypes contains the reflect type of the builtin types that args are converted to.
+var builtinReflectTypes = map[string]reflect.Type{
+	"string":  reflect.TypeOf(""),
+	"bool":    reflect.TypeOf(false),
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// convert returns arg as the Go type of the parameter of v in the generated function, untyped ints and floats become int64 and
+// float64. False is returned when arg doesn't fit the type of v.
+func (v *Var) convert(arg any) (any, bool) {
+	varType := v.Type
+	if builtin, ok := builtinTypes[v.GoType]; ok {
+		varType = builtin
+	}
+
+	if arg == nil {
+		return nil, varType == VarTypeAny || varType == VarTypeError
+	}
+
+	value := reflect.ValueOf(arg)
+	if v.GoType != "" && value.Type().String() == v.GoType {
+		return arg, true
+	}
+
+	// Values of a type in a package must have that type.
+	if _, ok := builtinTypes[v.GoType]; v.GoType != "" && !ok {
+		return nil, false
+	}
+
+	kind := value.Kind()
+	isInt := kind >= reflect.Int && kind <= reflect.Uintptr
+	isFloat := kind == reflect.Float32 || kind == reflect.Float64
+
+	var target reflect.Type
+	switch varType {
+	case VarTypeInt:
+		if !isInt {
+			return nil, false
+		}
+
+		target = builtinReflectTypes["int64"]
+	case VarTypeFloat:
+		if !isInt && !isFloat {
+			return nil, false
+		}
+
+		target = builtinReflectTypes["float64"]
+	case VarTypeString:
+		if kind != reflect.String {
+			return nil, false
+		}
+
+		target = builtinReflectTypes["string"]
+	case VarTypeBool:
+		if kind != reflect.Bool {
+			return nil, false
+		}
+
+		target = builtinReflectTypes["bool"]
+	case VarTypeTime:
+		return arg, value.Type() == timeType
+	case VarTypeDuration:
+		return arg, value.Type() == durationType
+	case VarTypeError:
+		return arg, value.Type().Implements(errorType)
+	default:
+		return arg, true
+	}
+
+	if declared, ok := builtinReflectTypes[v.GoType]; ok {
+		target = declared
+	}
+
+	// Convert wraps numbers that don't fit the target, like a uint64 above math.MaxInt64 or 1000 as an int8.
+	if !fits(value, target) {
+		return nil, false
+	}
+
+	return value.Convert(target).Interface(), true
+}
+
+// fits reports if the number value has the same value after a conversion to target, other values always fit.
+func fits(value reflect.Value, target reflect.Type) bool {
+	zero := reflect.Zero(target)
+	signed := target.Kind() >= reflect.Int && target.Kind() <= reflect.Int64
+	unsigned := target.Kind() >= reflect.Uint && target.Kind() <= reflect.Uintptr
+	float := target.Kind() == reflect.Float32 || target.Kind() == reflect.Float64
+
+	switch {
+	case value.CanInt():
+		n := value.Int()
+		switch {
+		case signed:
+			return !zero.OverflowInt(n)
+		case unsigned:
+			return n >= 0 && !zero.OverflowUint(uint64(n))
+		case float:
+			return !zero.OverflowFloat(float64(n))
+		}
+	case value.CanUint():
+		n := value.Uint()
+		switch {
+		case signed:
+			return n <= math.MaxInt64 && !zero.OverflowInt(int64(n))
+		case unsigned:
+			return !zero.OverflowUint(n)
+		case float:
+			return !zero.OverflowFloat(float64(n))
+		}
+	case value.CanFloat():
+		return !float || !zero.OverflowFloat(value.Float())
+	}
+
+	return true
+}
+
+// Arg returns args[i] as a T, the zero value of T is returned for a nil arg. The generated render functions use it.
+func Arg[T any](args []any, i int) T {
+	value, _ := args[i].(T)
+	return value
+}
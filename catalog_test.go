@@ -0,0 +1,115 @@
+package staticmessages_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+type userID uint32
+
+func newCatalog() *staticmessages.Catalog {
+	catalog := staticmessages.NewCatalog()
+	catalog.Register(
+		&staticmessages.CatalogEntry{
+			Identifier: "UserNotFound",
+			Vars: []*staticmessages.Var{
+				{Name: "ID", Type: staticmessages.VarTypeInt},
+				{Name: "ratio", Type: staticmessages.VarTypeFloat},
+			},
+			Render: func(ctx context.Context, args []any) string {
+				if staticmessages.GetLocale(ctx) == "nl" {
+					return fmt.Sprintf("Gebruiker %d niet gevonden", staticmessages.Arg[int64](args, 0))
+				}
+
+				return fmt.Sprintf("User %d not found, %.1f", staticmessages.Arg[int64](args, 0), staticmessages.Arg[float64](args, 1))
+			},
+		},
+		&staticmessages.CatalogEntry{
+			Identifier: "UserFailed",
+			Vars: []*staticmessages.Var{
+				{Name: "ID", Type: staticmessages.VarTypeInt, GoType: "uint32"},
+				{Name: "at", Type: staticmessages.VarTypeTime},
+				{Name: "cause", Type: staticmessages.VarTypeError},
+			},
+			Render: func(ctx context.Context, args []any) string {
+				return fmt.Sprintf("User %d failed at %s: %v", staticmessages.Arg[uint32](args, 0),
+					staticmessages.Arg[time.Time](args, 1).Format(time.DateOnly), staticmessages.Arg[error](args, 2))
+			},
+		},
+	)
+
+	return catalog
+}
+
+func TestCatalogRender(t *testing.T) {
+	catalog := newCatalog()
+	require.Equal(t, []string{"UserFailed", "UserNotFound"}, catalog.Identifiers())
+
+	msg, err := catalog.Render(context.Background(), "UserNotFound", map[string]any{"ID": 5, "ratio": 2})
+	require.NoError(t, err)
+	require.Equal(t, "User 5 not found, 2.0", msg)
+
+	msg, err = catalog.Render(staticmessages.WrapLocale(context.Background(), "nl"), "UserNotFound", map[string]any{"ID": userID(5), "ratio": 0.5})
+	require.NoError(t, err)
+	require.Equal(t, "Gebruiker 5 niet gevonden", msg)
+
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	msg, err = catalog.Render(context.Background(), "UserFailed", map[string]any{"ID": 7, "at": at, "cause": nil})
+	require.NoError(t, err)
+	require.Equal(t, "User 7 failed at 2024-01-02: <nil>", msg)
+
+	msg, err = catalog.Render(context.Background(), "UserFailed", map[string]any{"ID": uint32(7), "at": at, "cause": errors.New("timeout")})
+	require.NoError(t, err)
+	require.Equal(t, "User 7 failed at 2024-01-02: timeout", msg)
+}
+
+func TestCatalogRenderErrors(t *testing.T) {
+	catalog := newCatalog()
+
+	_, err := catalog.Render(context.Background(), "UserDeleted", nil)
+	require.ErrorIs(t, err, staticmessages.ErrUnknownMessage)
+
+	for _, args := range []map[string]any{
+		{"ID": 5},
+		{"ID": "5", "ratio": 1.5},
+		{"ID": 5.0, "ratio": 1.5},
+		{"ID": 5, "ratio": "1.5"},
+		{"ID": 5, "ratio": 1.5, "name": "Jane"},
+	} {
+		_, err = catalog.Render(context.Background(), "UserNotFound", args)
+		require.ErrorIs(t, err, staticmessages.ErrArgInvalid, args)
+	}
+
+	_, err = catalog.Render(context.Background(), "UserFailed", map[string]any{"ID": 7, "at": "2024-01-02", "cause": "timeout"})
+	require.ErrorIs(t, err, staticmessages.ErrArgInvalid)
+	require.ErrorContains(t, err, "at of UserFailed must be of type time, got string")
+	require.ErrorContains(t, err, "cause of UserFailed must be of type error, got string")
+
+	// Numbers that don't fit the type of the var are not wrapped.
+	for _, args := range []map[string]any{
+		{"ID": uint64(math.MaxUint64), "ratio": 1.5},
+		{"ID": uint(math.MaxInt64 + 1), "ratio": 1.5},
+	} {
+		_, err = catalog.Render(context.Background(), "UserNotFound", args)
+		require.ErrorIs(t, err, staticmessages.ErrArgInvalid, args)
+	}
+
+	for _, id := range []any{-1, int64(math.MaxUint32 + 1), uint64(math.MaxUint32 + 1)} {
+		_, err = catalog.Render(context.Background(), "UserFailed", map[string]any{"ID": id, "at": time.Now(), "cause": nil})
+		require.ErrorIs(t, err, staticmessages.ErrArgInvalid, id)
+	}
+
+	_, err = catalog.Render(context.Background(), "UserFailed", map[string]any{"ID": int8(math.MaxInt8), "at": time.Now(), "cause": nil})
+	require.NoError(t, err)
+
+	require.Panics(t, func() {
+		catalog.Register(&staticmessages.CatalogEntry{Identifier: "UserFailed"})
+	})
+}
@@ -1,17 +1,34 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/wvell/messages"
+	"github.com/wvell/staticmessages"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			export(os.Args[2:])
+			return
+		case "import":
+			importTranslations(os.Args[2:])
+			return
+		}
+	}
+
 	var pkg, src, target string
+	var recursive, genErrors, genLocalizables, genCatalog bool
 
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -19,18 +36,30 @@ func main() {
 		os.Exit(1)
 	}
 
-	flag.StringVar(&pkg, "pkg", "", "Package name for the generated code.")
-	flag.StringVar(&src, "src", cwd, "Location where the .yml files are stored (only .yml files are parsed).")
+	flag.StringVar(&pkg, "pkg", "", "Package name for the generated code, a _package key in a file overrides it.")
+	flag.StringVar(&src, "src", cwd, "Location where the .yml, .yaml and .json files are stored (only .yml, .yaml and .json files are parsed).")
 	flag.StringVar(&target, "target", cwd, "Location where the go translation files should be written.")
+	flag.BoolVar(&recursive, "r", false, "Generate a package for every subdirectory of src in the same subdirectory of target, named after the directory.")
+	flag.BoolVar(&genErrors, "errors", false, "Generate an error type for every message that is translated when it is rendered.")
+	flag.BoolVar(&genCatalog, "catalog", false, "Register the messages in a Catalog var per package to render them by name at runtime.")
+	flag.BoolVar(&genLocalizables, "localizables", false, "Generate a XxxMsg function for every message that returns a staticmessages.Localizable.")
 
 	flag.Usage = func() {
 		fmt.Fprint(os.Stderr, "Usage of msggen:\n\n")
-		fmt.Fprint(os.Stderr, `msggen generates translation files based on .yml files.
+		fmt.Fprint(os.Stderr, `msggen generates translation files based on .yml and .json files.
 
 To generate go translation files in the current working directory:
 	# Inside myproject/translations
 	$ msggen -pkg translations
 
+To generate a package for every subdirectory of translations, translations/billing becomes internal/messages/billing:
+	$ msggen -r -src translations -target internal/messages
+
+To export or import translation files for translators:
+	$ msggen export -format po -out translations/po
+	$ msggen import -format po -in translations/po
+	$ msggen export -format xliff -out translations/xliff
+
 Note: Files are never automaticly removed, use a scritp to remove old translation files before generating new ones.
 
 `)
@@ -40,54 +69,282 @@ Note: Files are never automaticly removed, use a scritp to remove old translatio
 	}
 	flag.Parse()
 
-	if pkg == "" {
+	if pkg == "" && !recursive {
 		fmt.Fprintln(os.Stderr, "Package name is required.")
 		os.Exit(1)
 	}
 
-	// Read all .yml files from src.
-	files, err := os.ReadDir(src)
+	dirs := []string{src}
+	if recursive {
+		dirs, err = sourceDirs(src)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Parse all files before writing anything so every error in every file is reported in a single run.
+	packages := make([]*sourcePackage, 0)
+	failed := false
+
+	for _, dir := range dirs {
+		sp, ok := parsePackage(src, dir, pkg)
+		if !ok {
+			failed = true
+			continue
+		}
+
+		if sp != nil {
+			packages = append(packages, sp)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+
+	opts := make([]staticmessages.WriteOption, 0)
+	if genErrors {
+		opts = append(opts, staticmessages.WithErrors())
+	}
+
+	if genLocalizables {
+		opts = append(opts, staticmessages.WithLocalizables())
+	}
+
+	if genCatalog {
+		opts = append(opts, staticmessages.WithCatalog())
+	}
+
+	for _, sp := range packages {
+		targetDir := filepath.Join(target, sp.dir)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating directory %s: %v\n", targetDir, err)
+			os.Exit(1)
+		}
+
+		generate(filepath.Join(targetDir, packageFileName+".go"), func(w io.Writer) error {
+			return staticmessages.WritePackage(sp.messages, sp.name, w, opts...)
+		})
+
+		for i, messages := range sp.messages {
+			generate(filepath.Join(targetDir, sp.names[i]+".go"), func(w io.Writer) error {
+				return staticmessages.Write(messages, sp.name, w, opts...)
+			})
+		}
+	}
+}
+
+// packageFileName is the name of the file with the code that the files of a package share.
+const packageFileName = "msggen"
+
+// generate writes the go file at path, an existing file is left as is when the code can't be generated.
+func generate(path string, write func(w io.Writer) error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing to file %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stdout, "Generated %s\n", path)
+}
+
+// sourcePackage contains the parsed messages of a source directory that are generated into a single Go package.
+type sourcePackage struct {
+	// dir is the directory relative to src, the package is written to the same directory relative to target.
+	dir  string
+	name string
+	// messages contains the parsed files, names contains the name of every file.
+	messages []*staticmessages.Messages
+	names    []string
+}
+
+// parsePackage parses the message files in dir, nil is returned when dir doesn't contain message files.
+// Subdirectories of src are named after the directory, pkg is the name of src. A _package key in a file overrides the name.
+// Errors are printed, false is returned if any of the files contains an error.
+func parsePackage(src, dir, pkg string) (*sourcePackage, bool) {
+	files, err := sourceFiles(dir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading directory: %v\n", err)
-		os.Exit(1)
+		return nil, false
 	}
 
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".yml") {
-			filename := filepath.Join(src, file.Name())
-			f, err := os.Open(filename)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading file %s: %v\n", filename, err)
-				os.Exit(1)
-			}
+	if len(files) == 0 {
+		return nil, true
+	}
 
-			strippedFilename := strings.TrimSuffix(file.Name(), ".yml")
+	sources, err := catalogs(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing files:\n%v\n", err)
+		return nil, false
+	}
 
-			// Parse yml file.
-			parsed, err := messages.Parse(strippedFilename, f)
-			f.Close()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing file %s: %v\n", filename, err)
-				os.Exit(1)
-			}
+	rel, err := filepath.Rel(src, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading directory: %v\n", err)
+		return nil, false
+	}
 
-			targetFile := filepath.Join(target, strippedFilename+".go")
+	sp := &sourcePackage{dir: rel, name: pkg}
+	if rel != "." {
+		sp.name = packageName(filepath.Base(dir))
+	}
 
-			// Generate go code.
-			f, err = os.OpenFile(targetFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error opening target file %s: %v\n", targetFile, err)
-				os.Exit(1)
-			}
+	ok := true
+	var packageFile string
 
-			err = messages.Write(parsed, pkg, f)
-			f.Close()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing to file %s: %v\n", targetFile, err)
-				os.Exit(1)
+	for _, c := range sources {
+		messages, err := c.parse()
+		if err != nil {
+			ok = false
+			printError(c.file, err)
+			continue
+		}
+
+		if messages.Package != "" {
+			if packageFile != "" && messages.Package != sp.name {
+				ok = false
+				fmt.Fprintf(os.Stderr, "Error parsing file %s: _package %q doesn't match %q of %s\n", c.file, messages.Package, sp.name, packageFile)
+				continue
 			}
 
-			fmt.Fprintf(os.Stdout, "Generated %s\n", targetFile)
+			sp.name = messages.Package
+			packageFile = c.file
+		}
+
+		if c.name == packageFileName {
+			ok = false
+			fmt.Fprintf(os.Stderr, "Error parsing file %s: %s is reserved for the generated package file\n", c.file, packageFileName)
+			continue
+		}
+
+		sp.messages = append(sp.messages, messages)
+		sp.names = append(sp.names, c.name)
+	}
+
+	if ok && sp.name == "" {
+		fmt.Fprintf(os.Stderr, "Error generating %s: no valid package name, set one with _package\n", dir)
+		return nil, false
+	}
+
+	return sp, ok
+}
+
+// packageName converts a directory name into a package name, billing-v2 becomes billingv2.
+// An empty string is returned when the name can't be used as a package name.
+func packageName(dir string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+
+		return -1
+	}, strings.ToLower(dir))
+
+	if !token.IsIdentifier(name) {
+		return ""
+	}
+
+	return name
+}
+
+// sourceDirs returns src and all directories below it, hidden directories are skipped.
+func sourceDirs(src string) ([]string, error) {
+	dirs := make([]string, 0)
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != src && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		dirs = append(dirs, path)
+		return nil
+	})
+
+	return dirs, err
+}
+
+// catalog is a messages file with the locale files that contain its translations.
+type catalog struct {
+	name        string
+	file        string
+	localeFiles []string
+}
+
+// catalogs groups files by name, errors.nl.yml and errors.de.json are locale files of errors.yml.
+func catalogs(files []string) ([]*catalog, error) {
+	result := make([]*catalog, 0)
+	byName := make(map[string]*catalog)
+	var errs []error
+
+	for _, filename := range files {
+		name, locale := staticmessages.SplitFileName(filename)
+		if locale != "" {
+			continue
+		}
+
+		// errors.yml and errors.json would both be written to errors.go.
+		if c, ok := byName[name]; ok {
+			errs = append(errs, fmt.Errorf("%s: %s is generated from the same file name", filename, c.file))
+			continue
+		}
+
+		c := &catalog{name: name, file: filename}
+		byName[name] = c
+		result = append(result, c)
+	}
+
+	for _, filename := range files {
+		name, locale := staticmessages.SplitFileName(filename)
+		if locale == "" {
+			continue
+		}
+
+		c, ok := byName[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: no messages file for %s", filename, name))
+			continue
+		}
+
+		c.localeFiles = append(c.localeFiles, filename)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+// parse parses the messages file and adds the translations of the locale files.
+func (c *catalog) parse() (*staticmessages.Messages, error) {
+	messages, err := staticmessages.ParseFile(c.file)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, filename := range c.localeFiles {
+		if err := staticmessages.ParseLocaleFile(messages, filename); err != nil {
+			errs = append(errs, err)
 		}
 	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return messages, nil
 }
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/wvell/staticmessages"
+)
+
+// translationFormat is a file format for translators.
+type translationFormat struct {
+	ext string
+	// template is the extension of the file without translations, no template is written when it is empty.
+	template string
+	write    func(msg *staticmessages.Messages, sourceLocale, locale string, w io.Writer) error
+	read     func(path string) (string, []*staticmessages.Unit, error)
+	// writeAll and readAll are used instead of write and read for formats with all locales in a single file.
+	writeAll func(msg *staticmessages.Messages, locales []string, w io.Writer) error
+	readAll  func(path string) ([]string, map[string][]*staticmessages.Unit, error)
+	// files is used instead of write for formats with a directory per locale, it returns the files of a locale.
+	// The default messages are exported as the empty locale, these formats can't be imported.
+	files func(base, sourceLocale, locale string) []exportFile
+}
+
+// exportFile is a file of a locale, path is relative to the export directory.
+type exportFile struct {
+	path  string
+	write func(msg *staticmessages.Messages, w io.Writer) error
+}
+
+var translationFormats = map[string]translationFormat{
+	"po": {
+		ext:      ".po",
+		template: ".pot",
+		write: func(msg *staticmessages.Messages, _, locale string, w io.Writer) error {
+			return staticmessages.WritePO(msg, locale, w)
+		},
+		read: staticmessages.ReadPOFile,
+	},
+	"xliff": {
+		ext:   ".xlf",
+		write: staticmessages.WriteXLIFF,
+		read:  staticmessages.ReadXLIFFFile,
+	},
+	"csv": {
+		ext:      ".csv",
+		writeAll: staticmessages.WriteCSV,
+		readAll:  staticmessages.ReadCSVFile,
+	},
+	"android": {
+		files: func(base, sourceLocale, locale string) []exportFile {
+			return []exportFile{{
+				path: filepath.Join(staticmessages.AndroidResourceDir(locale), base+".xml"),
+				write: func(msg *staticmessages.Messages, w io.Writer) error {
+					return staticmessages.WriteAndroid(msg, sourceLocale, locale, w)
+				},
+			}}
+		},
+	},
+	"i18next": {
+		files: func(base, sourceLocale, locale string) []exportFile {
+			dir := locale
+			if locale == "" {
+				dir = sourceLocale
+			}
+
+			return []exportFile{{
+				path: filepath.Join(dir, base+".json"),
+				write: func(msg *staticmessages.Messages, w io.Writer) error {
+					return staticmessages.WriteI18next(msg, sourceLocale, locale, w)
+				},
+			}}
+		},
+	},
+	"arb": {
+		files: func(base, sourceLocale, locale string) []exportFile {
+			arbLocale := locale
+			if locale == "" {
+				arbLocale = sourceLocale
+			}
+
+			return []exportFile{{
+				path: base + "_" + strings.ReplaceAll(arbLocale, "-", "_") + ".arb",
+				write: func(msg *staticmessages.Messages, w io.Writer) error {
+					return staticmessages.WriteARB(msg, sourceLocale, locale, w)
+				},
+			}}
+		},
+	},
+	"ios": {
+		files: func(base, sourceLocale, locale string) []exportFile {
+			dir := locale + ".lproj"
+			if locale == "" {
+				dir = sourceLocale + ".lproj"
+			}
+
+			return []exportFile{
+				{
+					path: filepath.Join(dir, base+".strings"),
+					write: func(msg *staticmessages.Messages, w io.Writer) error {
+						return staticmessages.WriteIOSStrings(msg, locale, w)
+					},
+				},
+				{
+					path: filepath.Join(dir, base+".stringsdict"),
+					write: func(msg *staticmessages.Messages, w io.Writer) error {
+						return staticmessages.WriteIOSStringsDict(msg, sourceLocale, locale, w)
+					},
+				},
+			}
+		},
+	},
+}
+
+// lookupFormat returns the translation format by name, the program exits when the format is unknown.
+func lookupFormat(name string) translationFormat {
+	format, ok := translationFormats[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unsupported format %q, use po, xliff, csv, android, ios, i18next or arb.\n", name)
+		os.Exit(1)
+	}
+
+	return format
+}
+
+// export writes a translation file for every locale of every message file in src.
+func export(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	src := flags.String("src", ".", "Location where the .yml and .json files are stored.")
+	out := flags.String("out", ".", "Location where the translation files should be written.")
+	formatName := flags.String("format", "po", "Format of the translation files: po, xliff, csv, android, ios, i18next or arb.")
+	locales := flags.String("locales", "", "Comma separated locales to export besides the locales that are already translated.")
+	sourceLocale := flags.String("source-locale", "en", "Locale of the default messages, used by the formats that export the default messages.")
+	flags.Parse(args)
+
+	format := lookupFormat(*formatName)
+
+	files, err := sourceFiles(*src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	sources, err := catalogs(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing files:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	for _, c := range sources {
+		messages, err := c.parse()
+		if err != nil {
+			printError(c.file, err)
+			os.Exit(1)
+		}
+
+		base := c.name
+
+		exportLocales := messages.Locales()
+		if format.template != "" || format.files != nil {
+			exportLocales = append([]string{""}, exportLocales...)
+		}
+
+		for _, locale := range strings.Split(*locales, ",") {
+			if locale = strings.TrimSpace(locale); locale != "" && !slices.Contains(exportLocales, locale) {
+				exportLocales = append(exportLocales, locale)
+			}
+		}
+
+		if format.files != nil {
+			for _, locale := range exportLocales {
+				for _, file := range format.files(base, *sourceLocale, locale) {
+					targetFile := filepath.Join(*out, file.path)
+					if err := os.MkdirAll(filepath.Dir(targetFile), 0755); err != nil {
+						fmt.Fprintf(os.Stderr, "Error creating directory %s: %v\n", filepath.Dir(targetFile), err)
+						os.Exit(1)
+					}
+
+					writeFile(targetFile, func(w io.Writer) error {
+						return file.write(messages, w)
+					})
+				}
+			}
+
+			continue
+		}
+
+		if format.writeAll != nil {
+			targetFile := filepath.Join(*out, base+format.ext)
+			writeFile(targetFile, func(w io.Writer) error {
+				return format.writeAll(messages, exportLocales, w)
+			})
+
+			continue
+		}
+
+		for _, locale := range exportLocales {
+			targetFile := filepath.Join(*out, base+format.template)
+			if locale != "" {
+				targetFile = filepath.Join(*out, base+"."+locale+format.ext)
+			}
+
+			writeFile(targetFile, func(w io.Writer) error {
+				return format.write(messages, *sourceLocale, locale, w)
+			})
+		}
+	}
+}
+
+// writeFile creates the file at path and writes it with write, the program exits on errors.
+func writeFile(path string, write func(w io.Writer) error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening target file %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	err = write(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing to file %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stdout, "Exported %s\n", path)
+}
+
+// importTranslations merges the translation files in the in directory into the .yml files in src.
+// A file named errors.nl.po or errors.nl.xlf is merged into errors.nl.yml if it exists and into errors.yml otherwise, nothing is written when any of the files contains an error.
+// A csv file like errors.csv contains the default messages and all locales.
+func importTranslations(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	src := flags.String("src", ".", "Location where the .yml files are stored.")
+	in := flags.String("in", ".", "Location of the translation files.")
+	formatName := flags.String("format", "po", "Format of the translation files: po, xliff or csv.")
+	flags.Parse(args)
+
+	format := lookupFormat(*formatName)
+	if format.read == nil && format.readAll == nil {
+		fmt.Fprintf(os.Stderr, "Format %q can only be exported.\n", *formatName)
+		os.Exit(1)
+	}
+
+	entries, err := os.ReadDir(*in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	im := &importer{src: *src, updated: make(map[string][]byte)}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != format.ext {
+			continue
+		}
+
+		filename := filepath.Join(*in, entry.Name())
+		base, fileLocale := staticmessages.SplitFileName(filename)
+
+		if format.readAll != nil {
+			locales, units, err := format.readAll(filename)
+			if err != nil {
+				im.failed = true
+				printError(filename, err)
+				continue
+			}
+
+			for _, locale := range locales {
+				im.apply(filename, base, locale, units[locale])
+			}
+
+			continue
+		}
+
+		locale, units, err := format.read(filename)
+		if err != nil {
+			im.failed = true
+			printError(filename, err)
+			continue
+		}
+
+		if locale == "" {
+			locale = fileLocale
+		}
+
+		if locale == "" {
+			im.failed = true
+			fmt.Fprintf(os.Stderr, "Error importing file %s: no Language header and no locale in the file name\n", filename)
+			continue
+		}
+
+		im.apply(filename, base, locale, units)
+	}
+
+	if im.failed {
+		os.Exit(1)
+	}
+
+	for _, target := range im.order {
+		if err := os.WriteFile(target, im.updated[target], 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing to file %s: %v\n", target, err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stdout, "Imported %s\n", target)
+	}
+}
+
+// importer collects the updated yml files of an import, files with multiple locales are updated once per locale.
+type importer struct {
+	src     string
+	updated map[string][]byte
+	// order contains the updated files in the order they are first updated.
+	order  []string
+	failed bool
+}
+
+// apply merges the units of filename into the yml file of base, an empty locale updates the default messages.
+// Translations are written to the locale file when the locale has one, errors.nl.yml for example.
+func (im *importer) apply(filename, base, locale string, units []*staticmessages.Unit) {
+	target := ymlFile(im.src, base)
+
+	var messages *staticmessages.Messages
+	if localeFile := ymlFile(im.src, base+"."+locale); locale != "" && fileExists(localeFile) {
+		var err error
+		messages, err = staticmessages.ParseFile(target)
+		if err != nil {
+			im.failed = true
+			printError(target, err)
+			return
+		}
+
+		target = localeFile
+	}
+
+	content, ok := im.updated[target]
+	if !ok {
+		var err error
+		content, err = os.ReadFile(target)
+		if err != nil {
+			im.failed = true
+			fmt.Fprintf(os.Stderr, "Error importing file %s: %v\n", filename, err)
+			return
+		}
+
+		im.order = append(im.order, target)
+	}
+
+	var err error
+	if messages != nil {
+		content, err = staticmessages.ImportLocaleUnits(target, content, messages, units)
+	} else {
+		content, err = staticmessages.ImportUnits(target, content, locale, units)
+	}
+	if err != nil {
+		im.failed = true
+		printError(filename, err)
+		return
+	}
+
+	im.updated[target] = content
+}
+
+// sourceFiles returns the .yml, .yaml and .json files in dir.
+func sourceFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yml" && ext != ".yaml" && ext != ".json") {
+			continue
+		}
+
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+
+	return files, nil
+}
+
+// ymlFile returns the .yaml file with name in dir if it exists and the .yml file otherwise.
+func ymlFile(dir, name string) string {
+	if path := filepath.Join(dir, name+".yaml"); fileExists(path) {
+		return path
+	}
+
+	return filepath.Join(dir, name+".yml")
+}
+
+// fileExists reports if path is an existing file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// printError prints err, parse errors contain the file and position in a format editors understand.
+func printError(filename string, err error) {
+	var parseErrs staticmessages.ParseErrors
+	if errors.As(err, &parseErrs) {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Error parsing file %s: %v\n", filename, err)
+}
@@ -0,0 +1,176 @@
+package staticmessages
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+var ErrCSVInvalid = errors.New("csv is invalid")
+
+// csvColumns are the columns of a CSV file before the locale columns.
+var csvColumns = []string{"id", "description", "default"}
+
+// WriteCSV writes the messages as CSV for spreadsheets, the columns are id, description, default and a column per locale.
+// Every unit is a row, a plural contains the categories of all locales. Cells of categories a locale doesn't use are empty,
+// including the default cell of categories that only translations use.
+func WriteCSV(msg *Messages, locales []string, w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(append(append([]string(nil), csvColumns...), locales...)); err != nil {
+		return err
+	}
+
+	for _, l := range msg.Messages {
+		ids := make([]string, 0)
+		// units contains the units of every locale by ID, the units of the default messages have an empty locale.
+		units := make(map[string]map[string]*Unit)
+
+		for _, locale := range append([]string{""}, locales...) {
+			var target *Message
+			if locale != "" {
+				target = l.Translation(locale)
+			}
+
+			localeIDs := make([]string, 0)
+			for _, unit := range l.units(locale, l.Default, target, nil, nil) {
+				if units[unit.ID] == nil {
+					units[unit.ID] = make(map[string]*Unit)
+				}
+
+				units[unit.ID][locale] = unit
+				localeIDs = append(localeIDs, unit.ID)
+			}
+
+			ids = mergeIDs(ids, localeIDs)
+		}
+
+		for _, id := range ids {
+			var source string
+			if unit := units[id][""]; unit != nil {
+				source = unit.Source
+			}
+
+			row := []string{id, docText(l), source}
+			for _, locale := range locales {
+				var target string
+				if unit := units[id][locale]; unit != nil {
+					target = unit.Target
+				}
+
+				row = append(row, target)
+			}
+
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// mergeIDs adds the ids that are missing in merged after the id that precedes them in ids.
+func mergeIDs(merged, ids []string) []string {
+	pos := 0
+	for _, id := range ids {
+		if i := slices.Index(merged, id); i >= 0 {
+			pos = i + 1
+			continue
+		}
+
+		merged = slices.Insert(merged, pos, id)
+		pos++
+	}
+
+	return merged
+}
+
+// ReadCSV reads the units of a CSV file written by WriteCSV, the units are returned by locale in the order of the columns.
+// The default column is returned as the units of the empty locale, the source of these units is empty.
+func ReadCSV(r io.Reader) ([]string, map[string][]*Unit, error) {
+	return readCSV("", r)
+}
+
+// ReadCSVFile reads the units of the CSV file at path, see ReadCSV.
+func ReadCSVFile(path string) ([]string, map[string][]*Unit, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	return readCSV(path, f)
+}
+
+func readCSV(file string, r io.Reader) ([]string, map[string][]*Unit, error) {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, ParseErrors{{File: file, Line: 1, Err: fmt.Errorf("%w: %v", ErrCSVInvalid, err)}}
+	}
+
+	for i, column := range csvColumns {
+		if i >= len(header) || header[i] != column {
+			return nil, nil, ParseErrors{{File: file, Line: 1, Column: i + 1, Err: fmt.Errorf("%w: expected column %q", ErrCSVInvalid, column)}}
+		}
+	}
+
+	locales := append([]string{""}, header[len(csvColumns):]...)
+	units := make(map[string][]*Unit)
+	var errs ParseErrors
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var line int
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				line = csvErr.Line
+			}
+
+			// The reader continues after errors in the number of fields only.
+			errs = append(errs, &ParseError{File: file, Line: line, Err: fmt.Errorf("%w: %v", ErrCSVInvalid, err)})
+			if !errors.Is(err, csv.ErrFieldCount) {
+				break
+			}
+
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+		identifier, keys := splitUnitID(row[0])
+		for i, locale := range locales {
+			unit := &Unit{
+				ID:         row[0],
+				Identifier: identifier,
+				Keys:       keys,
+				Target:     row[i+len(csvColumns)-1],
+				File:       file,
+				Line:       line,
+			}
+
+			if locale != "" {
+				unit.Source = row[len(csvColumns)-1]
+			}
+
+			units[locale] = append(units[locale], unit)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+
+	return locales, units, nil
+}
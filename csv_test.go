@@ -0,0 +1,85 @@
+package staticmessages_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestWriteCSV(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(poSource))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, staticmessages.WriteCSV(messages, []string{"nl", "pl"}, &buf))
+
+	require.Equal(t, `id,description,default,nl,pl
+NotFound,"Shown when a user cannot be found.
+
+Context: Error page title",User %(ID)d not found,Gebruiker %(ID)d niet gevonden,
+Files.=0,,No files in %(folder)s,,
+Files.one,,One file in %(folder)s,,
+Files.few,,,,
+Files.many,,,,
+Files.other,,%(count)d files in %(folder)s,,
+Welcome,,"Dear %(user)s,
+Welcome!
+",,
+`, buf.String())
+}
+
+func TestReadCSV(t *testing.T) {
+	t.Run("import", func(t *testing.T) {
+		locales, units, err := staticmessages.ReadCSV(strings.NewReader(`id,description,default,nl
+NotFound,,User %(ID)d was not found,Gebruiker %(ID)d is niet gevonden
+Files.=0,,No files in %(folder)s,Geen bestanden in %(folder)s
+Files.one,,One file in %(folder)s,Een bestand in %(folder)s
+Files.other,,%(count)d files in %(folder)s,%(count)d bestanden in %(folder)s
+`))
+		require.NoError(t, err)
+		require.Equal(t, []string{"", "nl"}, locales)
+		require.Len(t, units["nl"], 4)
+		require.Equal(t, "Files.one", units["nl"][2].ID)
+		require.Equal(t, "One file in %(folder)s", units["nl"][2].Source)
+		require.Equal(t, 4, units["nl"][2].Line)
+
+		updated := []byte(poSource)
+		for _, locale := range locales {
+			updated, err = staticmessages.ImportUnits("users.yml", updated, locale, units[locale])
+			require.NoError(t, err)
+		}
+
+		messages, err := staticmessages.Parse("users", bytes.NewReader(updated))
+		require.NoError(t, err)
+		require.Equal(t, "User %d was not found", messages.Messages[0].Default.Message)
+		require.Equal(t, "Gebruiker %d is niet gevonden", messages.Messages[0].Translation("nl").Message)
+		require.Equal(t, "Een bestand in %s", messages.Messages[1].Translation("nl").Variants[1].Message.Message)
+	})
+
+	t.Run("variable type mix", func(t *testing.T) {
+		locales, units, err := staticmessages.ReadCSV(strings.NewReader(`id,description,default,nl
+NotFound,,User %(ID)s not found,Gebruiker %(ID)s niet gevonden
+`))
+		require.NoError(t, err)
+
+		for _, locale := range locales {
+			_, err = staticmessages.ImportUnits("users.yml", []byte(poSource), locale, units[locale])
+			require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := staticmessages.ReadCSV(strings.NewReader("id,default,nl\n"))
+		require.ErrorIs(t, err, staticmessages.ErrCSVInvalid)
+
+		_, _, err = staticmessages.ReadCSV(strings.NewReader("id,description,default,nl\nNotFound,,User not found\n"))
+		require.ErrorIs(t, err, staticmessages.ErrCSVInvalid)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Equal(t, 2, parseErrs[0].Line)
+	})
+}
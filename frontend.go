@@ -0,0 +1,353 @@
+package staticmessages
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WriteI18next writes the messages of locale as an i18next JSON v4 resource file, an empty locale writes the default messages.
+// Placeholders are written as {{name}}. Plurals get a key per category of the locale like Files_one, the default messages use
+// the categories of sourceLocale. An =0 variant is written as the _zero key. Select variants are written as contexts like
+// Role_admin, the other variant is the key without context.
+func WriteI18next(msg *Messages, sourceLocale, locale string, w io.Writer) error {
+	categoryLocale := locale
+	if locale == "" {
+		categoryLocale = sourceLocale
+	}
+
+	resources := make(jsonObject, 0)
+	for _, l := range msg.Messages {
+		m := l.Default
+		if locale != "" {
+			m = l.Translation(locale)
+		}
+
+		if m == nil {
+			continue
+		}
+
+		e := &i18nextEntries{identifier: l.Identifier, message: m, locale: categoryLocale}
+		e.walk(l.Default, nil, "", "")
+		resources = append(resources, e.entries...)
+	}
+
+	return resources.write(w)
+}
+
+// i18nextEntries collects the keys of a message, the variants of the default message decide the keys.
+type i18nextEntries struct {
+	identifier string
+	// message is the message of the locale.
+	message *Message
+	locale  string
+	entries jsonObject
+}
+
+// walk adds the keys of the variants of def, keys leads to def and context and plural are the suffixes of the key.
+func (e *i18nextEntries) walk(def *Message, keys []string, context, plural string) {
+	with := func(key string) []string {
+		return append(keys[:len(keys):len(keys)], key)
+	}
+
+	if len(def.Variants) == 0 {
+		text := leafMessage(e.message, keys).replaceText(i18nextEscape, func(v *Var, _ string) string {
+			return "{{" + v.Name + "}}"
+		})
+
+		e.entries = append(e.entries, jsonField{key: e.identifier + context + plural, value: text})
+		return
+	}
+
+	if !def.IsPlural() {
+		for _, v := range def.Variants {
+			variantContext := context
+			if v.Key != "other" {
+				variantContext += "_" + v.Key
+			}
+
+			e.walk(v.Message, with(v.Key), variantContext, plural)
+		}
+
+		return
+	}
+
+	categories := PluralCategories(e.locale)
+	if !contains(categories, "other") {
+		categories = append(categories, "other")
+	}
+
+	// i18next uses the _zero key for a count of 0 in every language.
+	if v := def.variant("=0"); v != nil && !contains(categories, "zero") {
+		e.walk(v.Message, with(v.Key), context, "_zero")
+	}
+
+	for _, category := range categories {
+		v := def.variant(category)
+		if v == nil {
+			v = def.OtherVariant()
+		}
+
+		e.walk(v.Message, with(category), context, "_"+category)
+	}
+}
+
+// i18nextEscape escapes the literal text of a message for i18next. i18next has no escape for the {{ of a placeholder and the
+// $t( of a nested key, a zero width space keeps them from being read as such. The space is written after a { that is followed
+// by another { or by a placeholder.
+func i18nextEscape(text string) string {
+	text = strings.ReplaceAll(text, "$t(", "$\u200bt(")
+
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		b.WriteByte(text[i])
+		if text[i] == '{' && (i+1 == len(text) || text[i+1] == '{') {
+			b.WriteString("\u200b")
+		}
+	}
+
+	return b.String()
+}
+
+// WriteARB writes the messages of locale as a Flutter ARB file, an empty locale writes the default messages as the template
+// with sourceLocale as locale. Keys start with a lowercase letter as Flutter requires. Placeholders are written as {name} and
+// variants as ICU plurals and selects. The template contains the @key metadata with the description and placeholder types.
+func WriteARB(msg *Messages, sourceLocale, locale string, w io.Writer) error {
+	arbLocale := locale
+	if locale == "" {
+		arbLocale = sourceLocale
+	}
+
+	resources := jsonObject{{key: "@@locale", value: strings.ReplaceAll(arbLocale, "-", "_")}}
+	for _, l := range msg.Messages {
+		m := l.Default
+		if locale != "" {
+			m = l.Translation(locale)
+		}
+
+		if m == nil {
+			continue
+		}
+
+		key := arbKey(l.Identifier)
+		resources = append(resources, jsonField{key: key, value: icuText(m)})
+
+		if metadata := arbMetadata(l); locale == "" && len(metadata) > 0 {
+			resources = append(resources, jsonField{key: "@" + key, value: metadata})
+		}
+	}
+
+	return resources.write(w)
+}
+
+// arbKey returns the identifier with a lowercase first letter.
+func arbKey(identifier string) string {
+	r, size := utf8.DecodeRuneInString(identifier)
+	return string(unicode.ToLower(r)) + identifier[size:]
+}
+
+// icuText returns m in the ICU MessageFormat syntax with {name} placeholders.
+func icuText(m *Message) string {
+	return icuMessage(m, false)
+}
+
+// icuMessage returns m in the ICU MessageFormat syntax, plural is set when m is a variant of a plural.
+func icuMessage(m *Message, plural bool) string {
+	if len(m.Variants) == 0 {
+		return m.replaceText(func(text string) string {
+			return icuEscape(text, plural)
+		}, func(v *Var, _ string) string {
+			return "{" + v.Name + "}"
+		})
+	}
+
+	kind := "select"
+	if m.IsPlural() {
+		kind = "plural"
+	}
+
+	var b strings.Builder
+	b.WriteString("{" + m.Selector.Name + ", " + kind + ",")
+	for _, v := range m.Variants {
+		b.WriteString(" " + v.Key + "{" + icuMessage(v.Message, m.IsPlural()) + "}")
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// icuEscape quotes the ICU syntax characters in literal text with apostrophes, # is only a syntax character in the variants
+// of a plural. A quote contains every syntax character in a row, an apostrophe is doubled inside and outside a quote.
+func icuEscape(text string, plural bool) string {
+	var b strings.Builder
+	quoted := false
+
+	for _, r := range text {
+		switch {
+		case r == '\'':
+			b.WriteString("''")
+		case r == '{' || r == '}' || (plural && r == '#'):
+			if !quoted {
+				b.WriteString("'")
+				quoted = true
+			}
+
+			b.WriteRune(r)
+		default:
+			if quoted {
+				b.WriteString("'")
+				quoted = false
+			}
+
+			b.WriteRune(r)
+		}
+	}
+
+	if quoted {
+		b.WriteString("'")
+	}
+
+	return b.String()
+}
+
+// arbTypes contains the ARB placeholder type of every var type, other types are passed as Object.
+var arbTypes = map[VarType]string{
+	VarTypeString: "String",
+	VarTypeInt:    "int",
+	VarTypeFloat:  "double",
+	VarTypeTime:   "DateTime",
+}
+
+// arbDateFormats contains the date format of the time layouts.
+var arbDateFormats = map[string]string{
+	time.DateTime: "yyyy-MM-dd HH:mm:ss",
+	time.DateOnly: "yyyy-MM-dd",
+	time.TimeOnly: "HH:mm:ss",
+}
+
+// arbMetadata returns the @key metadata of l.
+func arbMetadata(l *LocalizedMessage) jsonObject {
+	// The precision of the floats is part of the fmt verb.
+	precisions := make(map[string]string)
+	for _, m := range l.Default.leaves() {
+		m.replaceVars(false, func(v *Var, spec string) string {
+			if _, precision, ok := strings.Cut(spec, "."); ok && v.Type == VarTypeFloat {
+				precisions[v.Name] = precision
+			}
+
+			return ""
+		})
+	}
+
+	placeholders := make(jsonObject, 0)
+	for _, v := range l.UniqueVars() {
+		tp, ok := arbTypes[v.Type]
+		if !ok {
+			tp = "Object"
+		}
+
+		placeholder := jsonObject{{key: "type", value: tp}}
+
+		if precision, ok := precisions[v.Name]; ok {
+			placeholder = append(placeholder,
+				jsonField{key: "format", value: "decimalPatternDigits"},
+				jsonField{key: "optionalParameters", value: jsonObject{{key: "decimalDigits", value: json.Number(precision)}}},
+			)
+		}
+
+		if v.Type == VarTypeTime {
+			format, ok := arbDateFormats[v.Layout]
+			if !ok {
+				format = arbDateFormats[time.DateTime]
+			}
+
+			placeholder = append(placeholder, jsonField{key: "format", value: format}, jsonField{key: "isCustomDateFormat", value: "true"})
+		}
+
+		placeholders = append(placeholders, jsonField{key: v.Name, value: placeholder})
+	}
+
+	metadata := make(jsonObject, 0)
+	if text := docText(l); text != "" {
+		metadata = append(metadata, jsonField{key: "description", value: text})
+	}
+
+	if len(placeholders) > 0 {
+		metadata = append(metadata, jsonField{key: "placeholders", value: placeholders})
+	}
+
+	return metadata
+}
+
+// leaves returns the messages without variants in m.
+func (m *Message) leaves() []*Message {
+	if len(m.Variants) == 0 {
+		return []*Message{m}
+	}
+
+	leaves := make([]*Message, 0)
+	for _, v := range m.Variants {
+		leaves = append(leaves, v.Message.leaves()...)
+	}
+
+	return leaves
+}
+
+// jsonObject is a JSON object that keeps the order of the fields, the values are strings, numbers or objects.
+type jsonObject []jsonField
+
+type jsonField struct {
+	key   string
+	value any
+}
+
+// write writes the object indented by 2 spaces.
+func (o jsonObject) write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	o.writeIndented(bw, "")
+	bw.WriteString("\n")
+
+	return bw.Flush()
+}
+
+func (o jsonObject) writeIndented(w *bufio.Writer, indent string) {
+	if len(o) == 0 {
+		w.WriteString("{}")
+		return
+	}
+
+	w.WriteString("{\n")
+	for i, field := range o {
+		w.WriteString(indent + "  " + jsonString(field.key) + ": ")
+
+		switch value := field.value.(type) {
+		case jsonObject:
+			value.writeIndented(w, indent+"  ")
+		case json.Number:
+			w.WriteString(value.String())
+		case string:
+			w.WriteString(jsonString(value))
+		}
+
+		if i < len(o)-1 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString(indent + "}")
+}
+
+// jsonString returns s as a JSON string, HTML characters are not escaped.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
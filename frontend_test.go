@@ -0,0 +1,87 @@
+package staticmessages_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestWriteI18next(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(mobileSource))
+	require.NoError(t, err)
+
+	for locale, golden := range map[string]string{"": "i18next.golden_en", "pl": "i18next.golden_pl"} {
+		var buf bytes.Buffer
+		require.NoError(t, staticmessages.WriteI18next(messages, "en", locale, &buf))
+		require.True(t, json.Valid(buf.Bytes()))
+
+		compareGolden(t, buf.Bytes(), golden)
+	}
+}
+
+func TestWriteARB(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(mobileSource+`Due:
+  default: Due at %(at)t
+`))
+	require.NoError(t, err)
+
+	for locale, golden := range map[string]string{"": "arb.golden_en", "nl": "arb.golden_nl"} {
+		var buf bytes.Buffer
+		require.NoError(t, staticmessages.WriteARB(messages, "en", locale, &buf))
+		require.True(t, json.Valid(buf.Bytes()))
+
+		compareGolden(t, buf.Bytes(), golden)
+	}
+}
+
+const escapingSource = `Braces:
+  default: "Use {name} or {{name}} for %(user)s, it's $t(done)"
+Tags:
+  default:
+    _plural: count
+    =0: "# {%(group)s} has no tags"
+    one: "# One tag in {%(group)s}"
+    other: "'#' Tags in '{'%(group)s'}'"
+Role:
+  default:
+    _select: role
+    admin: "# admin's {{%(group)s}}"
+    other: "# user"
+`
+
+func TestWriteFrontendEscaping(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(escapingSource))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, staticmessages.WriteI18next(messages, "en", "", &buf))
+	require.True(t, json.Valid(buf.Bytes()))
+	compareGolden(t, buf.Bytes(), "i18next.golden_escaping")
+
+	buf.Reset()
+	require.NoError(t, staticmessages.WriteARB(messages, "en", "", &buf))
+	require.True(t, json.Valid(buf.Bytes()))
+	compareGolden(t, buf.Bytes(), "arb.golden_escaping")
+
+	// The ICU messages contain the same text as the source.
+	var arb map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &arb))
+
+	for _, l := range messages.Messages {
+		msg, err := staticmessages.ParseICUMessage(arb[strings.ToLower(l.Identifier[:1])+l.Identifier[1:]].(string))
+		require.NoError(t, err, l.Identifier)
+
+		if len(l.Default.Variants) == 0 {
+			require.Equal(t, l.Default.Message, msg.Message, l.Identifier)
+			continue
+		}
+
+		for i, v := range l.Default.Variants {
+			require.Equal(t, v.Message.Message, msg.Variants[i].Message.Message, l.Identifier+"."+v.Key)
+		}
+	}
+}
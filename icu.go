@@ -0,0 +1,446 @@
+package staticmessages
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrICUSyntax = errors.New("invalid ICU message syntax")
+
+	// icuDecimalRe matches a number skeleton with a fixed amount of fraction digits like ::.00.
+	icuDecimalRe = regexp.MustCompile(`^::\.(0+)$`)
+)
+
+// Syntax is the placeholder grammar of a message.
+type Syntax string
+
+var (
+	// SyntaxPrintf is the python like syntax: Hello %(name)s. It is the default syntax.
+	SyntaxPrintf Syntax = "printf"
+	// SyntaxICU is the ICU MessageFormat syntax: Hello {name}.
+	SyntaxICU Syntax = "icu"
+)
+
+// ParseMessage parses a message in the printf syntax, see ParsePrintfMessage. ICU messages are parsed with ParseICUMessage.
+func ParseMessage(raw string) (*Message, error) {
+	return ParsePrintfMessage(raw)
+}
+
+// parseMessageSyntax parses raw with the given syntax, an empty syntax is the printf syntax.
+func parseMessageSyntax(raw string, syntax Syntax) (*Message, error) {
+	if syntax == SyntaxICU {
+		return ParseICUMessage(raw)
+	}
+
+	return ParsePrintfMessage(raw)
+}
+
+// ParseICUMessage parses a message in the ICU MessageFormat syntax into the same model as ParsePrintfMessage.
+//
+// Supported arguments:
+//
+//	{name}                              string
+//	{count, number}                     integer
+//	{count, number, integer}            integer
+//	{total, number, ::.00}              float with 2 fraction digits
+//	{at, date}                          time.Time formatted as 2006-01-02
+//	{at, time}                          time.Time formatted as 15:04:05
+//	{count, plural, one {# file} other {# files}}
+//	{gender, select, male {He} other {They}}
+//
+// Text around a plural or select is moved into every variant, so a message can contain only one of them per level.
+// Nest them to combine a select and a plural.
+func ParseICUMessage(raw string) (*Message, error) {
+	p := &icuParser{raw: raw}
+
+	nodes, err := p.parseNodes(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.raw) {
+		return nil, p.errorf("unexpected %q", p.raw[p.pos])
+	}
+
+	msg, err := buildICUMessage(raw, nodes, "")
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Raw = raw
+
+	return msg, nil
+}
+
+// icuNode is a part of an ICU message: literal text, a # inside a plural or an argument.
+type icuNode struct {
+	text  string
+	pound bool
+	arg   *icuArg
+}
+
+type icuArg struct {
+	name     string
+	typ      string
+	style    string
+	variants []*icuVariant
+}
+
+type icuVariant struct {
+	key   string
+	nodes []*icuNode
+}
+
+type icuParser struct {
+	raw string
+	pos int
+}
+
+func (p *icuParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%q at offset %d: %s: %w", p.raw, p.pos, fmt.Sprintf(format, args...), ErrICUSyntax)
+}
+
+// parseNodes parses a message until the end of raw or the closing brace of a variant.
+func (p *icuParser) parseNodes(inPlural bool) ([]*icuNode, error) {
+	nodes := make([]*icuNode, 0)
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, &icuNode{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.raw) {
+		c := p.raw[p.pos]
+
+		switch {
+		case c == '}':
+			flush()
+			return nodes, nil
+		case c == '{':
+			flush()
+
+			arg, err := p.parseArg()
+			if err != nil {
+				return nil, err
+			}
+
+			nodes = append(nodes, &icuNode{arg: arg})
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, &icuNode{pound: true})
+			p.pos++
+		case c == '\'':
+			text.WriteString(p.parseQuoted(inPlural))
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	flush()
+
+	return nodes, nil
+}
+
+// parseQuoted parses an apostrophe: two apostrophes are a literal apostrophe and '{...}' quotes syntax characters.
+func (p *icuParser) parseQuoted(inPlural bool) string {
+	p.pos++
+
+	if p.pos < len(p.raw) && p.raw[p.pos] == '\'' {
+		p.pos++
+		return "'"
+	}
+
+	if p.pos >= len(p.raw) || !(strings.ContainsRune("{}|", rune(p.raw[p.pos])) || (inPlural && p.raw[p.pos] == '#')) {
+		return "'"
+	}
+
+	var text strings.Builder
+	for p.pos < len(p.raw) {
+		if p.raw[p.pos] == '\'' {
+			if p.pos+1 < len(p.raw) && p.raw[p.pos+1] == '\'' {
+				text.WriteByte('\'')
+				p.pos += 2
+				continue
+			}
+
+			p.pos++
+			break
+		}
+
+		text.WriteByte(p.raw[p.pos])
+		p.pos++
+	}
+
+	return text.String()
+}
+
+// parseArg parses an argument starting at the opening brace.
+func (p *icuParser) parseArg() (*icuArg, error) {
+	p.pos++
+	arg := &icuArg{}
+
+	arg.name = p.parseWord()
+	if arg.name == "" {
+		return nil, p.errorf("expected argument name")
+	}
+
+	if p.consume('}') {
+		return arg, nil
+	}
+
+	if !p.consume(',') {
+		return nil, p.errorf("expected , or } after argument %q", arg.name)
+	}
+
+	arg.typ = p.parseWord()
+
+	switch arg.typ {
+	case "plural", "select":
+		if !p.consume(',') {
+			return nil, p.errorf("expected , after %s", arg.typ)
+		}
+
+		if err := p.parseVariants(arg); err != nil {
+			return nil, err
+		}
+	case "":
+		return nil, p.errorf("expected argument type for %q", arg.name)
+	default:
+		if p.consume(',') {
+			// Skip nested braces so unsupported complex arguments are reported by type instead of as a syntax error.
+			start, depth := p.pos, 0
+			for p.pos < len(p.raw) && (depth > 0 || p.raw[p.pos] != '}') {
+				switch p.raw[p.pos] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				}
+
+				p.pos++
+			}
+
+			arg.style = strings.TrimSpace(p.raw[start:p.pos])
+		}
+	}
+
+	if !p.consume('}') {
+		return nil, p.errorf("expected } to close argument %q", arg.name)
+	}
+
+	return arg, nil
+}
+
+// parseVariants parses the key {message} pairs of a plural or select argument.
+func (p *icuParser) parseVariants(arg *icuArg) error {
+	for {
+		p.skipSpace()
+
+		if p.pos >= len(p.raw) || p.raw[p.pos] == '}' {
+			break
+		}
+
+		start := p.pos
+		for p.pos < len(p.raw) && !strings.ContainsRune("{} \t\n\r", rune(p.raw[p.pos])) {
+			p.pos++
+		}
+
+		key := p.raw[start:p.pos]
+		if key == "" {
+			return p.errorf("expected variant key for %q", arg.name)
+		}
+
+		if strings.HasPrefix(key, "offset:") {
+			return fmt.Errorf("%q's argument %q uses an offset: %w", p.raw, arg.name, ErrUnsupportedFormat)
+		}
+
+		if !p.consume('{') {
+			return p.errorf("expected { after variant %q", key)
+		}
+
+		nodes, err := p.parseNodes(arg.typ == "plural")
+		if err != nil {
+			return err
+		}
+
+		if !p.consume('}') {
+			return p.errorf("expected } to close variant %q", key)
+		}
+
+		arg.variants = append(arg.variants, &icuVariant{key: key, nodes: nodes})
+	}
+
+	if len(arg.variants) == 0 {
+		return p.errorf("expected variants for %q", arg.name)
+	}
+
+	return nil
+}
+
+// parseWord skips surrounding whitespace and returns the letters at the current position.
+func (p *icuParser) parseWord() string {
+	p.skipSpace()
+
+	start := p.pos
+	for p.pos < len(p.raw) && ((p.raw[p.pos] >= 'a' && p.raw[p.pos] <= 'z') || (p.raw[p.pos] >= 'A' && p.raw[p.pos] <= 'Z')) {
+		p.pos++
+	}
+
+	word := p.raw[start:p.pos]
+	p.skipSpace()
+
+	return word
+}
+
+// consume skips whitespace and advances past c if it is the next character.
+func (p *icuParser) consume(c byte) bool {
+	p.skipSpace()
+
+	if p.pos < len(p.raw) && p.raw[p.pos] == c {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *icuParser) skipSpace() {
+	for p.pos < len(p.raw) && strings.ContainsRune(" \t\n\r", rune(p.raw[p.pos])) {
+		p.pos++
+	}
+}
+
+// buildICUMessage converts parsed nodes into a message, pound is the selector # refers to.
+func buildICUMessage(raw string, nodes []*icuNode, pound string) (*Message, error) {
+	complexIndex := -1
+	for i, node := range nodes {
+		if node.arg == nil || (node.arg.typ != "plural" && node.arg.typ != "select") {
+			continue
+		}
+
+		if complexIndex >= 0 {
+			return nil, fmt.Errorf("%q contains more then one plural or select, nest them instead: %w", raw, ErrUnsupportedFormat)
+		}
+
+		complexIndex = i
+	}
+
+	if complexIndex >= 0 {
+		return buildICUVariants(raw, nodes, complexIndex, pound)
+	}
+
+	msg := &Message{
+		Vars: make([]*Var, 0),
+	}
+
+	var format strings.Builder
+	for _, node := range nodes {
+		var msgVar *Var
+
+		switch {
+		case node.pound:
+			msgVar = &Var{Name: pound, Type: VarTypeInt}
+			format.WriteString("%d")
+		case node.arg != nil:
+			var verb string
+			var err error
+
+			msgVar, verb, err = icuVar(raw, node.arg)
+			if err != nil {
+				return nil, err
+			}
+
+			format.WriteString(verb)
+		default:
+			format.WriteString(strings.ReplaceAll(node.text, "%", "%%"))
+			continue
+		}
+
+		if isReservedKeyword(msgVar.Name) {
+			return nil, ErrReservedKeyword
+		}
+
+		existing := msg.Var(msgVar.Name)
+		if existing != nil && existing.Type != msgVar.Type {
+			return nil, fmt.Errorf("%q's var %q has type %q and %q: %w", raw, msgVar.Name, existing.Type, msgVar.Type, ErrVariableTypeMix)
+		}
+
+		msg.Vars = append(msg.Vars, msgVar)
+	}
+
+	msg.Message = format.String()
+
+	return msg, nil
+}
+
+// buildICUVariants converts the plural or select at index into a message with variants.
+// The nodes before and after the argument are added to every variant.
+func buildICUVariants(raw string, nodes []*icuNode, index int, pound string) (*Message, error) {
+	arg := nodes[index].arg
+	if arg.typ == "plural" {
+		pound = arg.name
+	}
+
+	variants := make([]*Variant, 0, len(arg.variants))
+	for _, v := range arg.variants {
+		variantNodes := make([]*icuNode, 0, len(nodes)+len(v.nodes))
+		variantNodes = append(variantNodes, nodes[:index]...)
+		variantNodes = append(variantNodes, v.nodes...)
+		variantNodes = append(variantNodes, nodes[index+1:]...)
+
+		msg, err := buildICUMessage(raw, variantNodes, pound)
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, &Variant{
+			Key:     v.key,
+			Message: msg,
+		})
+	}
+
+	if arg.typ == "plural" {
+		return NewPluralMessage(arg.name, variants)
+	}
+
+	return NewSelectMessage(arg.name, variants)
+}
+
+// icuVar returns the var and fmt verb of a simple argument.
+func icuVar(raw string, arg *icuArg) (*Var, string, error) {
+	switch arg.typ {
+	case "":
+		return &Var{Name: arg.name, Type: VarTypeString}, "%s", nil
+	case "number":
+		if arg.style == "" || arg.style == "integer" {
+			return &Var{Name: arg.name, Type: VarTypeInt}, "%d", nil
+		}
+
+		if m := icuDecimalRe.FindStringSubmatch(arg.style); m != nil {
+			return &Var{Name: arg.name, Type: VarTypeFloat}, fmt.Sprintf("%%.%df", len(m[1])), nil
+		}
+
+		return nil, "", fmt.Errorf("%q's argument %q contains number style %q: %w", raw, arg.name, arg.style, ErrUnsupportedFormat)
+	case "date", "time":
+		if arg.style != "" {
+			return nil, "", fmt.Errorf("%q's argument %q contains %s style %q: %w", raw, arg.name, arg.typ, arg.style, ErrUnsupportedFormat)
+		}
+
+		layout := time.DateOnly
+		if arg.typ == "time" {
+			layout = time.TimeOnly
+		}
+
+		return &Var{Name: arg.name, Type: VarTypeTime, Layout: layout}, "%s", nil
+	}
+
+	return nil, "", fmt.Errorf("%q's argument %q contains type %q: %w", raw, arg.name, arg.typ, ErrUnsupportedFormat)
+}
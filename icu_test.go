@@ -0,0 +1,108 @@
+package staticmessages_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestParseICUMessage(t *testing.T) {
+	t.Run("simple arguments", func(t *testing.T) {
+		msg, err := staticmessages.ParseICUMessage("Hello {user}! You have {count, number} new messages, total {total, number, ::.00}.")
+		require.NoError(t, err)
+
+		require.Equal(t, "Hello %s! You have %d new messages, total %.2f.", msg.Message)
+		require.Len(t, msg.Vars, 3)
+		require.Equal(t, staticmessages.VarTypeString, msg.Vars[0].Type)
+		require.Equal(t, staticmessages.VarTypeInt, msg.Vars[1].Type)
+		require.Equal(t, staticmessages.VarTypeFloat, msg.Vars[2].Type)
+	})
+
+	t.Run("date and time", func(t *testing.T) {
+		msg, err := staticmessages.ParseICUMessage("Shipped on {at, date} at {at, time}")
+		require.NoError(t, err)
+
+		require.Equal(t, "Shipped on %s at %s", msg.Message)
+		require.Len(t, msg.UniqueVars(), 1)
+		require.Equal(t, staticmessages.VarTypeTime, msg.Vars[0].Type)
+		require.Equal(t, "2006-01-02", msg.Vars[0].Layout)
+		require.Equal(t, "15:04:05", msg.Vars[1].Layout)
+	})
+
+	t.Run("quoting and percent", func(t *testing.T) {
+		msg, err := staticmessages.ParseICUMessage("It''s 100% '{literal}' {name}")
+		require.NoError(t, err)
+		require.Equal(t, "It's 100%% {literal} %s", msg.Message)
+	})
+
+	t.Run("plural", func(t *testing.T) {
+		msg, err := staticmessages.ParseICUMessage("In {folder}: {count, plural, =0 {no files} one {# file} other {# files}}.")
+		require.NoError(t, err)
+
+		require.True(t, msg.IsPlural())
+		require.Equal(t, "count", msg.Selector.Name)
+		require.Len(t, msg.Variants, 3)
+		require.Equal(t, "=0", msg.Variants[0].Key)
+		require.Equal(t, "In %s: no files.", msg.Variants[0].Message.Message)
+		require.Equal(t, "In %s: %d files.", msg.Variants[2].Message.Message)
+		require.Equal(t, "count", msg.Variants[2].Message.Vars[1].Name)
+		require.Len(t, msg.UniqueVars(), 2)
+	})
+
+	t.Run("nested select and plural", func(t *testing.T) {
+		msg, err := staticmessages.ParseICUMessage("{gender, select, male {He has {count, plural, one {# item} other {# items}}} other {They have # items}}")
+		require.NoError(t, err)
+
+		require.True(t, msg.IsSelect())
+		require.True(t, msg.Variants[0].Message.IsPlural())
+		require.Equal(t, "He has %d item", msg.Variants[0].Message.Variants[0].Message.Message)
+		// A # outside of a plural is literal text.
+		require.Equal(t, "They have # items", msg.Variants[1].Message.Message)
+	})
+
+	t.Run("same format as printf", func(t *testing.T) {
+		icu, err := staticmessages.ParseICUMessage("Hello {user}, you have {n, number} messages")
+		require.NoError(t, err)
+
+		printf, err := staticmessages.ParsePrintfMessage("Hello %(user)s, you have %(n)d messages")
+		require.NoError(t, err)
+
+		require.Equal(t, printf.Message, icu.Message)
+		require.Equal(t, printf.Vars, icu.Vars)
+	})
+
+	errorCases := []struct {
+		raw      string
+		expected error
+	}{
+		{raw: "Hello {user", expected: staticmessages.ErrICUSyntax},
+		{raw: "Hello {}", expected: staticmessages.ErrICUSyntax},
+		{raw: "Hello }", expected: staticmessages.ErrICUSyntax},
+		{raw: "{count, plural, one {# file}", expected: staticmessages.ErrICUSyntax},
+		{raw: "{count, plural}", expected: staticmessages.ErrICUSyntax},
+		{raw: "{count, plural, one {# file}}", expected: staticmessages.ErrOtherVariantMissing},
+		{raw: "{count, plural, several {#} other {#}}", expected: staticmessages.ErrPluralKeyInvalid},
+		{raw: "{count, plural, offset:1 other {#}}", expected: staticmessages.ErrUnsupportedFormat},
+		{raw: "{count, selectordinal, other {#}}", expected: staticmessages.ErrUnsupportedFormat},
+		{raw: "{total, number, percent}", expected: staticmessages.ErrUnsupportedFormat},
+		{raw: "{at, date, short}", expected: staticmessages.ErrUnsupportedFormat},
+		{raw: "{a, select, other {A}} {b, select, other {B}}", expected: staticmessages.ErrUnsupportedFormat},
+		{raw: "{user} {user, number}", expected: staticmessages.ErrVariableTypeMix},
+		{raw: "{type}", expected: staticmessages.ErrReservedKeyword},
+	}
+
+	for _, c := range errorCases {
+		t.Run(c.raw, func(t *testing.T) {
+			_, err := staticmessages.ParseICUMessage(c.raw)
+			require.ErrorIs(t, err, c.expected)
+		})
+	}
+}
+
+func TestParseMessageUsesPrintf(t *testing.T) {
+	msg, err := staticmessages.ParseMessage("Use {name} here, it's %(count)d")
+	require.NoError(t, err)
+	require.Equal(t, "Use {name} here, it's %d", msg.Message)
+	require.Len(t, msg.Vars, 1)
+}
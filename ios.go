@@ -0,0 +1,106 @@
+package staticmessages
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteIOSStrings writes the messages of locale as an iOS .strings file, an empty locale writes the default messages.
+// Every messages file is its own table instead of a shared Localizable.strings, so the files can be exported one at a time.
+// Look up the strings with the name of the file as table name.
+// Placeholders are positional like %1$@ so translations can change the order. Plurals are written by WriteIOSStringsDict
+// and selects are written as a string per variant.
+func WriteIOSStrings(msg *Messages, locale string, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("/* Code generated by \"msggen\"; DO NOT EDIT. */\n")
+
+	for _, r := range mobileResources(msg) {
+		m := r.message(locale)
+		if m == nil || r.plural {
+			continue
+		}
+
+		bw.WriteString("\n")
+
+		if text := docText(r.l); text != "" {
+			bw.WriteString("/* " + strings.ReplaceAll(text, "*/", "* /") + " */\n")
+		}
+
+		bw.WriteString(iosQuote(r.name) + " = " + iosQuote(positionalFormat(m, r.l.UniqueVars(), iosConversion)) + ";\n")
+	}
+
+	return bw.Flush()
+}
+
+// WriteIOSStringsDict writes the plurals of locale as an iOS .stringsdict file, an empty locale writes the default messages
+// with the plural categories of sourceLocale. The categories are the rules of the locale, exact variants like =0 are left out.
+func WriteIOSStringsDict(msg *Messages, sourceLocale, locale string, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	bw.WriteString("<!-- Code generated by \"msggen\"; DO NOT EDIT. -->\n")
+	bw.WriteString("<!DOCTYPE plist PUBLIC \"-//Apple//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n")
+	bw.WriteString("<plist version=\"1.0\">\n<dict>\n")
+
+	categoryLocale := locale
+	if locale == "" {
+		categoryLocale = sourceLocale
+	}
+
+	for _, r := range mobileResources(msg) {
+		m := r.message(locale)
+		if m == nil || !r.plural {
+			continue
+		}
+
+		vars := r.l.UniqueVars()
+		selector := r.l.Default.Selector
+
+		position := 0
+		for i, v := range vars {
+			if v.Name == selector.Name {
+				position = i + 1
+			}
+		}
+
+		bw.WriteString("\t<key>" + xmlEscape(r.name) + "</key>\n\t<dict>\n")
+		bw.WriteString("\t\t<key>NSStringLocalizedFormatKey</key>\n")
+		bw.WriteString("\t\t<string>%" + strconv.Itoa(position) + "$#@" + selector.Name + "@</string>\n")
+		bw.WriteString("\t\t<key>" + selector.Name + "</key>\n\t\t<dict>\n")
+		bw.WriteString("\t\t\t<key>NSStringFormatSpecTypeKey</key>\n\t\t\t<string>NSStringPluralRuleType</string>\n")
+		bw.WriteString("\t\t\t<key>NSStringFormatValueTypeKey</key>\n\t\t\t<string>" + iosConversion(selector) + "</string>\n")
+
+		categories, messages := pluralQuantities(m, categoryLocale)
+		for i, category := range categories {
+			bw.WriteString("\t\t\t<key>" + category + "</key>\n")
+			bw.WriteString("\t\t\t<string>" + xmlEscape(positionalFormat(messages[i], vars, iosConversion)) + "</string>\n")
+		}
+
+		bw.WriteString("\t\t</dict>\n\t</dict>\n")
+	}
+
+	bw.WriteString("</dict>\n</plist>\n")
+
+	return bw.Flush()
+}
+
+func iosConversion(v *Var) string {
+	switch v.Type {
+	case VarTypeInt:
+		return "ld"
+	case VarTypeFloat:
+		return "f"
+	}
+
+	return "@"
+}
+
+// iosEscaper escapes the characters that have a meaning in a quoted .strings value.
+var iosEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+
+func iosQuote(text string) string {
+	return `"` + iosEscaper.Replace(text) + `"`
+}
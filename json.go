@@ -0,0 +1,198 @@
+package staticmessages
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrJSONInvalid = errors.New("json is invalid")
+
+// ParseJSON parses JSON messages from r, the JSON has the same structure as the yml files:
+//
+//	{
+//	  "HelloUser": {
+//	    "default": "Hello %(user)s!",
+//	    "nl": "Hallo %(user)s!"
+//	  }
+//	}
+//
+// All errors in r are returned as ParseErrors.
+func ParseJSON(name string, r io.Reader) (*Messages, error) {
+	return parseJSON("", name, r)
+}
+
+func parseJSON(file, name string, r io.Reader) (*Messages, error) {
+	if len(name) == 0 {
+		return nil, ErrYamlNameInvalid
+	}
+
+	node, err := decodeJSON(file, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNode(file, name, node)
+}
+
+// decodeJSON decodes the JSON document in r into a yml document node.
+func decodeJSON(file string, r io.Reader) (yaml.Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return yaml.Node{}, err
+	}
+
+	d := &jsonDecoder{data: data, decoder: json.NewDecoder(bytes.NewReader(data))}
+	d.decoder.UseNumber()
+
+	node, err := d.decodeValue()
+	if err == nil {
+		// Only a single value is allowed in the document.
+		if _, tokenErr := d.token(); tokenErr != io.EOF {
+			err = d.errorf("unexpected data after the top level value")
+		}
+	}
+
+	if err != nil {
+		line, column := d.position(d.offset)
+		return yaml.Node{}, ParseErrors{{File: file, Line: line, Column: column, Err: err}}
+	}
+
+	return yaml.Node{Kind: yaml.DocumentNode, Line: 1, Column: 1, Content: []*yaml.Node{node}}, nil
+}
+
+// jsonDecoder converts JSON into a yaml.Node tree so the yml parser can be used for JSON files.
+// Objects keep the key order of the source and every node has the line and column of it's token.
+type jsonDecoder struct {
+	data    []byte
+	decoder *json.Decoder
+	// offset is the start of the last read token.
+	offset int
+}
+
+// token reads the next token and records it's offset.
+func (d *jsonDecoder) token() (json.Token, error) {
+	// The decoder offset points after the previous token, skip the whitespace and separators to find the start of the next.
+	offset := int(d.decoder.InputOffset())
+	for offset < len(d.data) && bytes.IndexByte([]byte(" \t\r\n,:"), d.data[offset]) >= 0 {
+		offset++
+	}
+
+	d.offset = offset
+
+	return d.decoder.Token()
+}
+
+// decodeValue decodes the next value into a node.
+func (d *jsonDecoder) decodeValue() (*yaml.Node, error) {
+	tok, err := d.token()
+	if err != nil {
+		return nil, d.wrap(err)
+	}
+
+	line, column := d.position(d.offset)
+	node := &yaml.Node{Line: line, Column: column}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			node.Kind = yaml.MappingNode
+			node.Tag = "!!map"
+
+			keys := make(map[string]bool)
+			for d.decoder.More() {
+				key, err := d.decodeValue()
+				if err != nil {
+					return nil, err
+				}
+
+				// A duplicate key would silently override the first value, the error points at the second key.
+				if keys[key.Value] {
+					return nil, d.errorf("duplicate key %q", key.Value)
+				}
+
+				keys[key.Value] = true
+
+				value, err := d.decodeValue()
+				if err != nil {
+					return nil, err
+				}
+
+				node.Content = append(node.Content, key, value)
+			}
+		case '[':
+			node.Kind = yaml.SequenceNode
+			node.Tag = "!!seq"
+
+			for d.decoder.More() {
+				value, err := d.decodeValue()
+				if err != nil {
+					return nil, err
+				}
+
+				node.Content = append(node.Content, value)
+			}
+		default:
+			return nil, d.errorf("unexpected %q", tok)
+		}
+
+		// Read the closing delimiter.
+		if _, err := d.token(); err != nil {
+			return nil, d.wrap(err)
+		}
+	case string:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!str"
+		node.Value = tok
+	case json.Number:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!float"
+		node.Value = tok.String()
+	case bool:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!bool"
+		node.Value = fmt.Sprint(tok)
+	case nil:
+		node.Kind = yaml.ScalarNode
+		node.Tag = "!!null"
+		node.Value = "null"
+	}
+
+	return node, nil
+}
+
+// position returns the line and column of offset, both start at 1.
+func (d *jsonDecoder) position(offset int) (int, int) {
+	if offset > len(d.data) {
+		offset = len(d.data)
+	}
+
+	before := d.data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+func (d *jsonDecoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrJSONInvalid, fmt.Sprintf(format, args...))
+}
+
+func (d *jsonDecoder) wrap(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	// Syntax errors contain the exact offset of the error.
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		d.offset = int(syntaxErr.Offset)
+	}
+
+	return fmt.Errorf("%w: %v", ErrJSONInvalid, err)
+}
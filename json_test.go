@@ -0,0 +1,109 @@
+package staticmessages_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestParseJSON(t *testing.T) {
+	t.Run("same model as yml", func(t *testing.T) {
+		fromJSON, err := staticmessages.ParseJSON("users", strings.NewReader(`{
+  "UserNotFound": {
+    "_description": "Shown when a user cannot be found.",
+    "default": "User %(ID)d not found",
+    "nl": "Gebruiker %(ID)d niet gevonden"
+  },
+  "Files": {
+    "default": {
+      "_plural": "count",
+      "one": "One file",
+      "other": "%(count)d files"
+    }
+  },
+  "Admin": {
+    "Banned": {"default": "User %(ID)d is banned"}
+  }
+}`))
+		require.NoError(t, err)
+
+		fromYml, err := staticmessages.Parse("users", strings.NewReader(`UserNotFound:
+  _description: Shown when a user cannot be found.
+  default: User %(ID)d not found
+  nl: Gebruiker %(ID)d niet gevonden
+Files:
+  default:
+    _plural: count
+    one: One file
+    other: "%(count)d files"
+Admin:
+  Banned:
+    default: User %(ID)d is banned
+`))
+		require.NoError(t, err)
+
+		require.Equal(t, fromYml, fromJSON)
+	})
+
+	t.Run("key order", func(t *testing.T) {
+		container, err := staticmessages.ParseJSON("order", strings.NewReader(`{"Zebra": {"default": "Z"}, "Apple": {"default": "A"}, "Mango": {"default": "M"}}`))
+		require.NoError(t, err)
+
+		require.Len(t, container.Messages, 3)
+		require.Equal(t, "Zebra", container.Messages[0].Identifier)
+		require.Equal(t, "Apple", container.Messages[1].Identifier)
+		require.Equal(t, "Mango", container.Messages[2].Identifier)
+	})
+
+	t.Run("error position", func(t *testing.T) {
+		_, err := staticmessages.ParseJSON("errors", strings.NewReader(`{
+  "HelloUser": {
+    "default": "Hello, %(user)s!",
+    "nl": "Hallo, %(user)d!"
+  }
+}`))
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+
+		var parseErr *staticmessages.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 4, parseErr.Line)
+		require.Equal(t, 11, parseErr.Column)
+		require.Equal(t, "nl", parseErr.Locale)
+	})
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		_, err := staticmessages.ParseJSON("errors", strings.NewReader(`{
+  "HelloUser": {
+    "default": "Hello, %(user)s!",
+    "default": "Hi, %(user)s!"
+  }
+}`))
+		require.ErrorIs(t, err, staticmessages.ErrJSONInvalid)
+
+		var parseErr *staticmessages.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 4, parseErr.Line)
+		require.Equal(t, 5, parseErr.Column)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		for _, raw := range []string{`{"Hello": {"default": "Hello"}`, `{"Hello": }`, `{} {}`, ``} {
+			_, err := staticmessages.ParseJSON("invalid", strings.NewReader(raw))
+			require.ErrorIs(t, err, staticmessages.ErrJSONInvalid, raw)
+		}
+	})
+
+	t.Run("parse file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "errors.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"NotFound": {"default": "Not found"}}`), 0644))
+
+		container, err := staticmessages.ParseFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "Errors", container.Name)
+		require.Equal(t, "NotFound", container.Messages[0].Identifier)
+	})
+}
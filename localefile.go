@@ -0,0 +1,320 @@
+package staticmessages
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownIdentifier = errors.New("unknown identifier")
+
+// ParseLocale parses the yml translations of locale from r and adds them to messages.
+// A locale file contains the translated value of every message, groups are written like in the messages file:
+//
+//	HelloUser: Hallo %(user)s!
+//	Files:
+//	  _plural: count
+//	  one: 1 bestand
+//	  other: "%(count)d bestanden"
+//
+// Translations of identifiers that are not in messages and translations that already exist are returned as ParseErrors.
+// Messages is not changed when the translations in r can't be parsed.
+func ParseLocale(messages *Messages, locale string, r io.Reader) error {
+	node, err := decode("", r)
+	if err != nil {
+		return err
+	}
+
+	return parseLocaleNode("", messages, locale, node)
+}
+
+// ParseLocaleFile parses the locale file at path and adds the translations to messages, see ParseLocale.
+// The locale is part of the file name, errors.nl.yml contains the nl translations of errors.yml.
+// Files with the .json extension are parsed as JSON.
+func ParseLocaleFile(messages *Messages, path string) error {
+	_, locale := SplitFileName(path)
+	if locale == "" {
+		return &ParseError{File: path, Err: ErrYamlNameInvalid}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var node yaml.Node
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		node, err = decodeJSON(path, f)
+	} else {
+		node, err = decode(path, f)
+	}
+	if err != nil {
+		return err
+	}
+
+	return parseLocaleNode(path, messages, locale, node)
+}
+
+// SplitFileName returns the name and locale of a messages file.
+// The locale is empty for files without a locale, errors.yml returns errors and errors.nl.yml returns errors and nl.
+func SplitFileName(path string) (string, string) {
+	base := filepath.Base(path)
+	name, locale, _ := strings.Cut(strings.TrimSuffix(base, filepath.Ext(base)), ".")
+
+	return name, locale
+}
+
+// ImportLocaleUnits sets the targets of units as the translations in the yml locale file src and returns the updated document.
+// Messages are the messages of the messages file, they are not changed. See ImportUnits for the handling of the units.
+func ImportLocaleUnits(file string, src []byte, messages *Messages, units []*Unit) ([]byte, error) {
+	_, locale := SplitFileName(file)
+	if locale == "" {
+		return nil, &ParseError{File: file, Err: ErrYamlNameInvalid}
+	}
+
+	merged := messages.clone()
+	if err := parseLocale(file, merged, locale, src); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, err
+	}
+
+	// An empty locale file doesn't contain a document yet.
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	root := doc.Content[0]
+	p := &parser{file: file}
+	p.parseSyntax(root)
+
+	changed, err := importTargets(merged, locale, p.syntax, units)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]localeValue)
+	localeValues(root, "", values)
+
+	for _, l := range merged.Messages {
+		messageUnits, ok := changed[l.Identifier]
+		if !ok {
+			continue
+		}
+
+		// New translations are added at the end of the file.
+		value, ok := values[l.Identifier]
+		if !ok {
+			value = localeValue{mapping: root, key: l.Identifier}
+		}
+
+		setMappingValue(value.mapping, value.key, unitsNode(messageUnits, 0))
+	}
+
+	clearMergeTags(&doc)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+
+	// Check the result like ImportUnits does.
+	if err := parseLocale(file, messages.clone(), locale, buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func parseLocale(file string, messages *Messages, locale string, src []byte) error {
+	// An empty file has no translations.
+	if len(bytes.TrimSpace(src)) == 0 {
+		return nil
+	}
+
+	node, err := decode(file, bytes.NewReader(src))
+	if err != nil {
+		return err
+	}
+
+	return parseLocaleNode(file, messages, locale, node)
+}
+
+// localeValue is the position of a translation in a locale file.
+type localeValue struct {
+	mapping *yaml.Node
+	key     string
+}
+
+// localeValues collects the position of every translation in a locale file by identifier, groups are handled like parseLocaleGroup does.
+func localeValues(node *yaml.Node, prefix string, values map[string]localeValue) {
+	for i := 0; (i + 1) < len(node.Content); i += 2 {
+		key := node.Content[i]
+		value := node.Content[i+1]
+
+		// Merged translations are shared, they are overridden by a key in the file itself.
+		if isMergeKey(key) {
+			continue
+		}
+
+		if value.Kind == yaml.MappingNode && !isVariants(value) {
+			localeValues(value, prefix+key.Value, values)
+			continue
+		}
+
+		values[prefix+key.Value] = localeValue{mapping: node, key: key.Value}
+	}
+}
+
+// clone returns a copy of c that can get translations without changing the messages of c.
+func (c Messages) clone() *Messages {
+	clone := &Messages{Name: c.Name, Package: c.Package, Messages: make([]*LocalizedMessage, 0, len(c.Messages))}
+	for _, l := range c.Messages {
+		copied := *l
+		copied.Translations = append([]*Translation(nil), l.Translations...)
+		clone.Messages = append(clone.Messages, &copied)
+	}
+
+	return clone
+}
+
+// Lookup returns the message with identifier, nil is returned if there is no such message.
+func (c Messages) Lookup(identifier string) *LocalizedMessage {
+	for _, l := range c.Messages {
+		if l.Identifier == identifier {
+			return l
+		}
+	}
+
+	return nil
+}
+
+// localeTranslation is a parsed translation that is added once the whole file is parsed.
+type localeTranslation struct {
+	node    *yaml.Node
+	message *LocalizedMessage
+	value   *Message
+}
+
+func parseLocaleNode(file string, messages *Messages, locale string, node yaml.Node) error {
+	p := &parser{file: file}
+
+	root := p.root(&node)
+	if root == nil {
+		return p.errs
+	}
+
+	p.parseSyntax(root)
+
+	translations := make([]*localeTranslation, 0)
+	p.parseLocaleGroup(root, "", messages, locale, &translations)
+
+	if len(p.errs) > 0 {
+		return p.errs
+	}
+
+	// Every translation is checked before the first one is added, messages is not changed when one of them is invalid.
+	for _, tr := range translations {
+		if err := tr.message.checkTranslation(locale, tr.value); err != nil {
+			p.errorf(tr.node, tr.message.Identifier, locale, "%w: %w", ErrYamlDefinitionInvalid, err)
+		}
+	}
+
+	if len(p.errs) > 0 {
+		return p.errs
+	}
+
+	for _, tr := range translations {
+		if err := tr.message.AddTranslation(locale, tr.value); err != nil {
+			p.errorf(tr.node, tr.message.Identifier, locale, "%w: %w", ErrYamlDefinitionInvalid, err)
+		}
+	}
+
+	if len(p.errs) > 0 {
+		return p.errs
+	}
+
+	return nil
+}
+
+// parseLocaleGroup parses the translations in node, a mapping without _plural or _select key is a group.
+func (p *parser) parseLocaleGroup(node *yaml.Node, prefix string, messages *Messages, locale string, translations *[]*localeTranslation) {
+	for i := 0; (i + 1) < len(node.Content); i += 2 {
+		identifier := node.Content[i]
+		value := node.Content[i+1]
+
+		if identifier.Kind != yaml.ScalarNode {
+			p.errorf(identifier, "", locale, "%w: expected yaml.ScalarNode got %s", ErrYamlDefinitionInvalid, kindName(identifier.Kind))
+			continue
+		}
+
+		// File options are handled by parseSyntax, _shared contains anchors for aliases.
+		if prefix == "" && (identifier.Value == "_syntax" || identifier.Value == "_shared") {
+			continue
+		}
+
+		name := prefix + identifier.Value
+
+		if !identifierRe.MatchString(identifier.Value) {
+			p.errorf(identifier, name, locale, "%w: %w", ErrYamlDefinitionInvalid, ErrIdentifierInvalid)
+			continue
+		}
+
+		if value.Kind == yaml.MappingNode && !isVariants(value) {
+			p.parseLocaleGroup(value, name, messages, locale, translations)
+			continue
+		}
+
+		l := messages.Lookup(name)
+		if l == nil {
+			p.errorf(identifier, name, locale, "%w: %q is not defined in %s", ErrUnknownIdentifier, name, messages.Name)
+			continue
+		}
+
+		// The syntax of the locale file takes precedence over the syntax of the message.
+		syntax := p.syntax
+		if syntax == "" {
+			syntax = l.Syntax
+		}
+
+		msg := p.parseValue(value, name, locale, syntax)
+		if msg == nil {
+			continue
+		}
+
+		// Duplicates are reported before anything is added, the translation can be part of the messages file, another locale file or this file.
+		duplicate := l.Translation(locale) != nil
+		for _, tr := range *translations {
+			duplicate = duplicate || tr.message == l
+		}
+
+		if duplicate {
+			p.errorf(identifier, name, locale, "%w: %w: locale = %q", ErrYamlDefinitionInvalid, ErrDuplicateTranslation, locale)
+			continue
+		}
+
+		*translations = append(*translations, &localeTranslation{node: value, message: l, value: msg})
+	}
+}
+
+// isVariants reports if the mapping is a plural or select message.
+func isVariants(spec *yaml.Node) bool {
+	for i := 0; i < len(spec.Content); i += 2 {
+		if spec.Content[i].Value == "_plural" || spec.Content[i].Value == "_select" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,157 @@
+package staticmessages_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+const localeSource = `NotFound:
+  default: User %(ID)d not found
+  de: Benutzer %(ID)d nicht gefunden
+Files:
+  default:
+    _plural: count
+    one: One file
+    other: "%(count)d files"
+User:
+  Deleted:
+    default: User deleted
+`
+
+func TestParseLocale(t *testing.T) {
+	parse := func(t *testing.T) *staticmessages.Messages {
+		messages, err := staticmessages.Parse("users", strings.NewReader(localeSource))
+		require.NoError(t, err)
+
+		return messages
+	}
+
+	t.Run("merge translations", func(t *testing.T) {
+		messages := parse(t)
+
+		err := staticmessages.ParseLocale(messages, "nl", strings.NewReader(`NotFound: Gebruiker %(ID)d niet gevonden
+Files:
+  _plural: count
+  one: Een bestand
+  other: "%(count)d bestanden"
+User:
+  Deleted: Gebruiker verwijderd
+`))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"de", "nl"}, messages.Locales())
+		require.Equal(t, "Gebruiker %d niet gevonden", messages.Lookup("NotFound").Translation("nl").Message)
+		require.True(t, messages.Lookup("Files").Translation("nl").IsPlural())
+		require.Equal(t, "Gebruiker verwijderd", messages.Lookup("UserDeleted").Translation("nl").Message)
+	})
+
+	t.Run("unknown identifiers and duplicates", func(t *testing.T) {
+		messages := parse(t)
+
+		err := staticmessages.ParseLocale(messages, "de", strings.NewReader(`NotFound: Nicht gefunden %(ID)d
+Removed: Entfernt
+UserDeleted: Benutzer gelöscht
+User:
+  Deleted: Benutzer gelöscht
+`))
+		require.ErrorIs(t, err, staticmessages.ErrUnknownIdentifier)
+		require.ErrorIs(t, err, staticmessages.ErrDuplicateTranslation)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 3)
+		require.Equal(t, "NotFound", parseErrs[0].Identifier)
+		require.Equal(t, "de", parseErrs[0].Locale)
+		require.Equal(t, 1, parseErrs[0].Line)
+		require.Equal(t, "Removed", parseErrs[1].Identifier)
+		require.Equal(t, "UserDeleted", parseErrs[2].Identifier)
+		require.Equal(t, 5, parseErrs[2].Line)
+
+		// Nothing is added when the file contains errors.
+		require.Nil(t, messages.Lookup("UserDeleted").Translation("de"))
+	})
+
+	t.Run("var types", func(t *testing.T) {
+		err := staticmessages.ParseLocale(parse(t), "nl", strings.NewReader(`NotFound: Gebruiker %(ID)s niet gevonden`))
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	})
+
+	t.Run("invalid second translation", func(t *testing.T) {
+		messages := parse(t)
+
+		err := staticmessages.ParseLocale(messages, "nl", strings.NewReader(`NotFound: Gebruiker %(ID)d niet gevonden
+Files: "%(count)s bestanden"
+`))
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 1)
+		require.Equal(t, "Files", parseErrs[0].Identifier)
+
+		// The valid first translation is not added either.
+		require.Nil(t, messages.Lookup("NotFound").Translation("nl"))
+		require.Nil(t, messages.Lookup("Files").Translation("nl"))
+	})
+}
+
+func TestParseLocaleFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.nl.json"), []byte(`{"User": {"Deleted": "Gebruiker verwijderd"}}`), 0644))
+
+	messages, err := staticmessages.Parse("users", strings.NewReader(localeSource))
+	require.NoError(t, err)
+
+	require.NoError(t, staticmessages.ParseLocaleFile(messages, filepath.Join(dir, "users.nl.json")))
+	require.Equal(t, "Gebruiker verwijderd", messages.Lookup("UserDeleted").Translation("nl").Message)
+
+	name, locale := staticmessages.SplitFileName(filepath.Join(dir, "users.nl.json"))
+	require.Equal(t, "users", name)
+	require.Equal(t, "nl", locale)
+
+	name, locale = staticmessages.SplitFileName("users.yml")
+	require.Equal(t, "users", name)
+	require.Equal(t, "", locale)
+}
+
+func TestImportLocaleUnits(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(localeSource))
+	require.NoError(t, err)
+
+	_, units, err := staticmessages.ReadPO(strings.NewReader(`msgctxt "NotFound"
+msgid "User %(ID)d not found"
+msgstr "Gebruiker %(ID)d is niet gevonden"
+
+msgctxt "Files.one"
+msgid "One file"
+msgstr "Een bestand"
+
+msgctxt "Files.other"
+msgid "%(count)d files"
+msgstr "%(count)d bestanden"
+`))
+	require.NoError(t, err)
+
+	updated, err := staticmessages.ImportLocaleUnits("users.nl.yml", []byte(`NotFound: Gebruiker %(ID)d niet gevonden
+User:
+  Deleted: Gebruiker verwijderd
+`), messages, units)
+	require.NoError(t, err)
+
+	require.Equal(t, `NotFound: Gebruiker %(ID)d is niet gevonden
+User:
+  Deleted: Gebruiker verwijderd
+Files:
+  _plural: count
+  one: Een bestand
+  other: '%(count)d bestanden'
+`, string(updated))
+
+	// The messages of the messages file are not changed.
+	require.Nil(t, messages.Lookup("NotFound").Translation("nl"))
+}
@@ -0,0 +1,38 @@
+package staticmessages
+
+import "context"
+
+// Localizable is a message that is rendered when it is needed, in the locale of whoever reads it.
+type Localizable interface {
+	// Localize renders the message in the locale of ctx.
+	Localize(ctx context.Context) string
+	// LocalizeIn renders the message in locale.
+	LocalizeIn(locale string) string
+}
+
+// LocalizableMessage is the Localizable of a generated message, it holds the identifier and the vars of the message.
+type LocalizableMessage struct {
+	Identifier string
+	// Args contains the vars in the order of the parameters of the generated function.
+	Args   []any
+	render func(ctx context.Context) string
+}
+
+// NewLocalizable returns the Localizable of the message identifier, render renders the message with args in the locale of a ctx.
+// The generated XxxMsg functions use it.
+func NewLocalizable(identifier string, args []any, render func(ctx context.Context) string) *LocalizableMessage {
+	return &LocalizableMessage{Identifier: identifier, Args: args, render: render}
+}
+
+func (m *LocalizableMessage) Localize(ctx context.Context) string {
+	return m.render(ctx)
+}
+
+func (m *LocalizableMessage) LocalizeIn(locale string) string {
+	return m.render(WrapLocale(context.Background(), locale))
+}
+
+// String returns the default message.
+func (m *LocalizableMessage) String() string {
+	return m.render(context.Background())
+}
@@ -0,0 +1,29 @@
+package staticmessages_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestLocalizable(t *testing.T) {
+	var msg staticmessages.Localizable = staticmessages.NewLocalizable("NotFound", []any{5}, func(ctx context.Context) string {
+		if staticmessages.GetLocale(ctx) == "nl" {
+			return "Gebruiker 5 niet gevonden"
+		}
+
+		return "User 5 not found"
+	})
+
+	require.Equal(t, "Gebruiker 5 niet gevonden", msg.LocalizeIn("nl"))
+	require.Equal(t, "Gebruiker 5 niet gevonden", msg.Localize(staticmessages.WrapLocale(context.Background(), "nl")))
+	require.Equal(t, "User 5 not found", msg.Localize(context.Background()))
+	require.Equal(t, "User 5 not found", fmt.Sprint(msg))
+
+	m := msg.(*staticmessages.LocalizableMessage)
+	require.Equal(t, "NotFound", m.Identifier)
+	require.Equal(t, []any{5}, m.Args)
+}
@@ -4,21 +4,37 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 var (
 	ErrReservedKeyword      = errors.New("reserved keyword")
 	ErrIdentifierInvalid    = errors.New("identifier must start with an uppercase letter and contain only letters and numbers")
-	ErrUnsupportedFormat    = errors.New("format only supports 's', 'd' and float formatting with 'f', '9f', '.2f', '9.2f' or '9f")
+	ErrUnsupportedFormat    = errors.New("format only supports 's', 'd', 'b', 't', 'D', 'v', 'e' and float formatting with 'f', '9f', '.2f', '9.2f' or '9f")
 	ErrVariableTypeMix      = errors.New("a variable can only be of one type")
 	ErrDuplicateTranslation = errors.New("duplicate translation")
 	ErrDuplicateIdentifier  = errors.New("duplicate identifier")
+	ErrReservedIdentifier   = errors.New("identifier is reserved for the generated code")
+	ErrSelectorInvalid      = errors.New("selector must be a var name containing only letters")
+	ErrPluralKeyInvalid     = errors.New("plural key must be zero, one, two, few, many, other or an exact match like =0")
+	ErrSelectKeyInvalid     = errors.New("select key must not be empty or start with an underscore")
+	ErrDuplicateVariant     = errors.New("duplicate variant")
+	ErrOtherVariantMissing  = errors.New("variants require an other fallback")
+	ErrGoTypeInvalid        = errors.New("go type must be a builtin type or a type in a package like example.com/ids.UserID")
+	ErrUnknownVar           = errors.New("unknown variable")
+	ErrBareVerb             = errors.New("fmt verbs must be written as %(name)verb placeholders, use %% for a literal percent sign")
 
 	identifierRe = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	varNameRe    = regexp.MustCompile(`^[a-zA-Z]+$`)
 
-	varRe   = regexp.MustCompile(`%\(([a-zA-Z]+)\)([0-9\.]*[a-z]{1})`)
+	// varRe matches a placeholder with an optional Go type like %(ID:int64)d.
+	varRe   = regexp.MustCompile(`%\(([a-zA-Z]+)(?::([^)]+))?\)([0-9\.]*[a-zA-Z]{1})`)
 	floatRe = regexp.MustCompile(`^([0-9]+)?\.?([0-9]+)?f$`)
+	// bareVerbRe matches a fmt verb without a var name like %s or %-5.2f.
+	// The space flag is left out so text like "100% sure" is not seen as a verb.
+	bareVerbRe = regexp.MustCompile(`^%[-+#0]*[0-9]*(\.[0-9]*)?[a-zA-Z]`)
 )
 
 // NewLocalizedMessage creates a new message container.
@@ -27,6 +43,10 @@ func NewLocalizedMessage(identifier string, defaultMessage *Message) (*Localized
 		return nil, ErrIdentifierInvalid
 	}
 
+	if err := resolveGoTypes(defaultMessage); err != nil {
+		return nil, err
+	}
+
 	return &LocalizedMessage{
 		Identifier:   identifier,
 		Default:      defaultMessage,
@@ -34,38 +54,55 @@ func NewLocalizedMessage(identifier string, defaultMessage *Message) (*Localized
 	}, nil
 }
 
-func ParseMessage(raw string) (*Message, error) {
+// ParsePrintfMessage parses a message with %(name)verb placeholders like "Hello %(user)s".
+// A literal percent sign can be written as % or %%, bare fmt verbs like %s are rejected with ErrBareVerb.
+func ParsePrintfMessage(raw string) (*Message, error) {
 	msg := &Message{
-		Message: raw,
-		Vars:    make([]*Var, 0),
+		Vars: make([]*Var, 0),
 	}
 
-	for _, varMatch := range varRe.FindAllStringSubmatch(raw, -1) {
+	var format strings.Builder
+	offset := 0
+
+	for _, loc := range varRe.FindAllStringSubmatchIndex(raw, -1) {
+		literal, err := escapeLiteral(raw, raw[offset:loc[0]])
+		if err != nil {
+			return nil, err
+		}
+
+		format.WriteString(literal)
+		offset = loc[1]
+
+		varMatch := []string{raw[loc[0]:loc[1]], raw[loc[2]:loc[3]], raw[loc[6]:loc[7]]}
 
 		if isReservedKeyword(varMatch[1]) {
 			return nil, ErrReservedKeyword
 		}
 
+		msgVar := &Var{
+			Name: varMatch[1],
+		}
+		fmtVerb := varMatch[2]
+
 		// Check if the format is a float format and it is valid.
 		if strings.HasSuffix(varMatch[2], "f") {
 			if !floatRe.MatchString(varMatch[2]) {
 				return nil, fmt.Errorf("%q's var %q contains invalid float format %q: %w", raw, varMatch[1], varMatch[2], ErrUnsupportedFormat)
 			}
-		} else if varMatch[2] != "s" && varMatch[2] != "d" {
-			return nil, fmt.Errorf("%q's var %q contains format %q: %w", raw, varMatch[1], varMatch[2], ErrUnsupportedFormat)
-		}
 
-		msgVar := &Var{
-			Name: varMatch[1],
+			msgVar.Type = VarTypeFloat
+		} else if verb, ok := verbs[varMatch[2]]; ok {
+			msgVar.Type = verb.varType
+			msgVar.Layout = verb.layout
+			fmtVerb = verb.fmtVerb
+		} else {
+			return nil, fmt.Errorf("%q's var %q contains format %q: %w", raw, varMatch[1], varMatch[2], ErrUnsupportedFormat)
 		}
 
-		switch varMatch[2] {
-		case "d":
-			msgVar.Type = VarTypeInt
-		case "s":
-			msgVar.Type = VarTypeString
-		default:
-			msgVar.Type = VarTypeFloat
+		if loc[4] >= 0 {
+			if err := msgVar.declare(raw[loc[4]:loc[5]]); err != nil {
+				return nil, fmt.Errorf("%q's var %q: %w", raw, varMatch[1], err)
+			}
 		}
 
 		// Check if the var already exists with a different format.
@@ -75,7 +112,148 @@ func ParseMessage(raw string) (*Message, error) {
 		}
 
 		msg.Vars = append(msg.Vars, msgVar)
-		msg.Message = strings.ReplaceAll(msg.Message, varMatch[0], "%"+varMatch[2])
+		format.WriteString("%" + fmtVerb)
+	}
+
+	literal, err := escapeLiteral(raw, raw[offset:])
+	if err != nil {
+		return nil, err
+	}
+
+	format.WriteString(literal)
+	msg.Message = format.String()
+	msg.Raw = raw
+
+	if err := resolveGoTypes(msg); err != nil {
+		return nil, fmt.Errorf("%q: %w", raw, err)
+	}
+
+	return msg, nil
+}
+
+// verb is a placeholder verb, it is rendered with fmtVerb in the generated code.
+type verb struct {
+	varType VarType
+	fmtVerb string
+	layout  string
+}
+
+// verbs contains the placeholder verbs by their letter, floats are handled separately because they can contain a width and precision.
+var verbs = map[string]verb{
+	"s": {varType: VarTypeString, fmtVerb: "s"},
+	"d": {varType: VarTypeInt, fmtVerb: "d"},
+	"b": {varType: VarTypeBool, fmtVerb: "t"},
+	"t": {varType: VarTypeTime, fmtVerb: "s", layout: time.DateTime},
+	"D": {varType: VarTypeDuration, fmtVerb: "s"},
+	"v": {varType: VarTypeAny, fmtVerb: "v"},
+	"e": {varType: VarTypeError, fmtVerb: "v"},
+}
+
+// escapeLiteral escapes the percent signs in the text between placeholders so fmt prints them as is.
+// An escaped %% is kept, a percent sign that starts a fmt verb is rejected.
+func escapeLiteral(raw, literal string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '%' {
+			b.WriteByte(literal[i])
+			continue
+		}
+
+		if strings.HasPrefix(literal[i:], "%%") {
+			b.WriteString("%%")
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(literal[i:], "%(") {
+			return "", fmt.Errorf("%q contains an incomplete placeholder: %w", raw, ErrUnsupportedFormat)
+		}
+
+		if verb := bareVerbRe.FindString(literal[i:]); verb != "" {
+			return "", fmt.Errorf("%q contains %q: %w", raw, verb, ErrBareVerb)
+		}
+
+		b.WriteString("%%")
+	}
+
+	return b.String(), nil
+}
+
+// NewPluralMessage creates a message that picks one of the variants by the CLDR plural category of the integer var selector.
+// Variants are keyed by plural category (zero, one, two, few, many, other) or by an exact match like =0.
+func NewPluralMessage(selector string, variants []*Variant) (*Message, error) {
+	return newVariantMessage(&Var{Name: selector, Type: VarTypeInt}, variants, func(key string) error {
+		if !isPluralKey(key) {
+			return fmt.Errorf("%q: %w", key, ErrPluralKeyInvalid)
+		}
+
+		return nil
+	})
+}
+
+// NewSelectMessage creates a message that picks the variant whose key equals the value of the string var selector.
+// The other variant is used when no key matches, for example:
+//
+//	male: He updated the document
+//	female: She updated the document
+//	other: They updated the document
+func NewSelectMessage(selector string, variants []*Variant) (*Message, error) {
+	return newVariantMessage(&Var{Name: selector, Type: VarTypeString}, variants, func(key string) error {
+		if key == "" || strings.HasPrefix(key, "_") {
+			return fmt.Errorf("%q: %w", key, ErrSelectKeyInvalid)
+		}
+
+		return nil
+	})
+}
+
+// newVariantMessage creates a message with variants, validKey checks the key of every variant.
+func newVariantMessage(selector *Var, variants []*Variant, validKey func(key string) error) (*Message, error) {
+	if !varNameRe.MatchString(selector.Name) {
+		return nil, fmt.Errorf("%q: %w", selector.Name, ErrSelectorInvalid)
+	}
+
+	if isReservedKeyword(selector.Name) {
+		return nil, ErrReservedKeyword
+	}
+
+	msg := &Message{
+		Vars:     []*Var{{Name: selector.Name, Type: selector.Type}},
+		Selector: selector,
+		Variants: variants,
+	}
+
+	hasOther := false
+	for i, variant := range variants {
+		if err := validKey(variant.Key); err != nil {
+			return nil, err
+		}
+
+		for _, prev := range variants[:i] {
+			if prev.Key == variant.Key {
+				return nil, fmt.Errorf("%w: key = %q", ErrDuplicateVariant, variant.Key)
+			}
+		}
+
+		if variant.Key == "other" {
+			hasOther = true
+		}
+
+		// The vars of all variants end up in the same function signature, they must have the same type.
+		if err := varTypesConsistent(msg, variant.Message); err != nil {
+			return nil, err
+		}
+
+		msg.Vars = append(msg.Vars, variant.Message.Vars...)
+	}
+
+	if !hasOther {
+		return nil, ErrOtherVariantMissing
+	}
+
+	if err := resolveGoTypes(msg); err != nil {
+		return nil, err
 	}
 
 	return msg, nil
@@ -83,11 +261,17 @@ func ParseMessage(raw string) (*Message, error) {
 
 type Messages struct {
 	// Name contains the capitalized filename without the extension.
-	Name     string
+	Name string
+	// Package is the Go package of the generated code, it is set by the _package key and empty otherwise.
+	Package  string
 	Messages []*LocalizedMessage
 }
 
 func (c *Messages) Add(m *LocalizedMessage) error {
+	if contains(reservedIdentifiers, m.Identifier) {
+		return fmt.Errorf("%w: %q", ErrReservedIdentifier, m.Identifier)
+	}
+
 	for _, msg := range c.Messages {
 		if msg.Identifier == m.Identifier {
 			return ErrDuplicateIdentifier
@@ -130,6 +314,81 @@ func (c Messages) HasType(tp VarType) bool {
 	return false
 }
 
+// Locales returns the locales of all translations in the order they first appear.
+func (c Messages) Locales() []string {
+	locales := make([]string, 0)
+	for _, m := range c.Messages {
+		for _, tr := range m.Translations {
+			if !contains(locales, tr.Locale) {
+				locales = append(locales, tr.Locale)
+			}
+		}
+	}
+
+	return locales
+}
+
+// Imports returns the import paths the generated code needs besides fmt and context.
+func (c Messages) Imports() []string {
+	return c.imports(false)
+}
+
+// imports returns the import paths of the generated code, runtime adds the staticmessages package when the messages don't need it.
+func (c Messages) imports(runtime bool) []string {
+	var needsTime bool
+	declared := make([]string, 0)
+
+	for _, l := range c.Messages {
+		for _, m := range l.messages() {
+			for _, v := range m.Vars {
+				switch {
+				case v.Import != "":
+					if !contains(declared, v.Import) {
+						declared = append(declared, v.Import)
+					}
+				case v.GoType == "" && (v.Type == VarTypeTime || v.Type == VarTypeDuration):
+					needsTime = true
+				}
+			}
+		}
+	}
+
+	imports := make([]string, 0, len(declared)+2)
+	if needsTime {
+		imports = append(imports, "time")
+	}
+
+	if runtime || c.HasTranslations() || c.HasPlurals() {
+		imports = append(imports, "github.com/wvell/staticmessages")
+	}
+
+	sort.Strings(declared)
+	for _, path := range declared {
+		if !contains(imports, path) {
+			imports = append(imports, path)
+		}
+	}
+
+	return imports
+}
+
+// HasPlurals checks if any of the messages or translations is pluralized.
+func (c Messages) HasPlurals() bool {
+	for _, message := range c.Messages {
+		if message.Default.HasPlural() {
+			return true
+		}
+
+		for _, tr := range message.Translations {
+			if tr.Message.HasPlural() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (c Messages) HasTranslations() bool {
 	for _, message := range c.Messages {
 		if len(message.Translations) > 0 {
@@ -146,6 +405,20 @@ type LocalizedMessage struct {
 	Default    *Message
 	// Translations contains translations by locale.
 	Translations []*Translation
+
+	// Description explains the message to developers and translators.
+	Description string
+	// Context describes where the message is shown.
+	Context string
+	// Example is an example of the rendered message.
+	Example string
+	// Syntax is the syntax of the texts, set with _syntax in the message or at the top of the file. It is empty for printf.
+	Syntax Syntax
+}
+
+// HasDoc checks if the message contains any documentation.
+func (l *LocalizedMessage) HasDoc() bool {
+	return l.Description != "" || l.Context != "" || l.Example != ""
 }
 
 type Translation struct {
@@ -168,6 +441,24 @@ func (l *LocalizedMessage) HasType(t VarType) bool {
 }
 
 func (l *LocalizedMessage) AddTranslation(locale string, message *Message) error {
+	if err := l.checkTranslation(locale, message); err != nil {
+		return err
+	}
+
+	if err := resolveGoTypes(append(l.messages(), message)...); err != nil {
+		return err
+	}
+
+	l.Translations = append(l.Translations, &Translation{
+		Locale:  locale,
+		Message: message,
+	})
+
+	return nil
+}
+
+// checkTranslation returns the error that AddTranslation returns for message without changing l.
+func (l *LocalizedMessage) checkTranslation(locale string, message *Message) error {
 	if err := varTypesConsistent(l.Default, message); err != nil {
 		return err
 	}
@@ -182,14 +473,80 @@ func (l *LocalizedMessage) AddTranslation(locale string, message *Message) error
 		}
 	}
 
-	l.Translations = append(l.Translations, &Translation{
-		Locale:  locale,
-		Message: message,
-	})
+	_, err := declaredGoTypes(append(l.messages(), message)...)
+	return err
+}
+
+// Translation returns the message of locale, nil is returned when the message is not translated.
+func (l *LocalizedMessage) Translation(locale string) *Message {
+	for _, tr := range l.Translations {
+		if tr.Locale == locale {
+			return tr.Message
+		}
+	}
 
 	return nil
 }
 
+// DeclareVar sets the Go type of every occurrence of the var name, see Var.GoType for the supported types.
+func (l *LocalizedMessage) DeclareVar(name, goType string) error {
+	vars := make([]*Var, 0)
+	for _, m := range l.messages() {
+		for _, v := range m.allVars() {
+			if v.Name == name {
+				vars = append(vars, v)
+			}
+		}
+	}
+
+	if len(vars) == 0 {
+		return fmt.Errorf("%w: %q", ErrUnknownVar, name)
+	}
+
+	declared := &Var{Name: name, Type: vars[0].Type}
+	if err := declared.declare(goType); err != nil {
+		return fmt.Errorf("var %q: %w", name, err)
+	}
+
+	for _, v := range vars {
+		if v.GoType != "" && v.GoType != declared.GoType {
+			return fmt.Errorf("variable %q has type %q and %q: %w", name, v.GoType, declared.GoType, ErrVariableTypeMix)
+		}
+	}
+
+	for _, v := range vars {
+		v.GoType = declared.GoType
+		v.Import = declared.Import
+	}
+
+	return nil
+}
+
+// TypeParams returns the type parameters of the generated function, these are the ints and floats without a Go type.
+func (l *LocalizedMessage) TypeParams() []VarType {
+	params := make([]VarType, 0, 2)
+	for _, typ := range []VarType{VarTypeInt, VarTypeFloat} {
+		for _, v := range l.UniqueVars() {
+			if v.Type == typ && v.GoType == "" {
+				params = append(params, typ)
+				break
+			}
+		}
+	}
+
+	return params
+}
+
+// messages returns the default message and the messages of all translations.
+func (l *LocalizedMessage) messages() []*Message {
+	messages := []*Message{l.Default}
+	for _, tr := range l.Translations {
+		messages = append(messages, tr.Message)
+	}
+
+	return messages
+}
+
 func (l *LocalizedMessage) UniqueVars() []*Var {
 	vars := l.Default.UniqueVars()
 
@@ -251,9 +608,95 @@ func (u UniqueTypes) Filter(typs ...VarType) []VarType {
 }
 
 // Message is a single message and it's vars.
+//
+// A message with variants has no Message of it's own, the Selector var picks one of the Variants instead.
+// Vars then contains the selector and the vars of all variants.
 type Message struct {
 	Message string
 	Vars    []*Var
+	// Raw is the source text the message was parsed from, it is empty for messages created from a mapping of variants.
+	Raw string
+
+	// Selector is the var that picks one of the Variants, nil for plain messages.
+	// An integer selector picks by plural category, a string selector picks by value.
+	Selector *Var
+	Variants []*Variant
+}
+
+// Variant is one of the forms of a plural or select message.
+type Variant struct {
+	// Key is the CLDR plural category or an exact match like =0 for plurals and the matched value for selects.
+	Key     string
+	Message *Message
+}
+
+// Exact returns the value of an exact match variant (=0 returns 0) or an empty string for plural categories.
+func (v *Variant) Exact() string {
+	if isExactKey(v.Key) {
+		return v.Key[1:]
+	}
+
+	return ""
+}
+
+// IsPlural checks if the message picks it's variant by plural category.
+func (m *Message) IsPlural() bool {
+	return m.Selector != nil && m.Selector.Type == VarTypeInt
+}
+
+// IsSelect checks if the message picks it's variant by the value of a string var.
+func (m *Message) IsSelect() bool {
+	return m.Selector != nil && m.Selector.Type == VarTypeString
+}
+
+// HasPlural checks if the message or one of it's variants is pluralized.
+func (m *Message) HasPlural() bool {
+	if m.IsPlural() {
+		return true
+	}
+
+	for _, v := range m.Variants {
+		if v.Message.HasPlural() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExactVariants returns the plural variants that match an exact value.
+func (m *Message) ExactVariants() []*Variant {
+	variants := make([]*Variant, 0)
+	for _, v := range m.Variants {
+		if v.Exact() != "" {
+			variants = append(variants, v)
+		}
+	}
+
+	return variants
+}
+
+// CategoryVariants returns the variants that match a plural category or a select value, except for the other fallback.
+func (m *Message) CategoryVariants() []*Variant {
+	variants := make([]*Variant, 0)
+	for _, v := range m.Variants {
+		if (m.IsSelect() || v.Exact() == "") && v.Key != "other" {
+			variants = append(variants, v)
+		}
+	}
+
+	return variants
+}
+
+// OtherVariant returns the fallback variant.
+func (m *Message) OtherVariant() *Variant {
+	for _, v := range m.Variants {
+		if v.Key == "other" {
+			return v
+		}
+	}
+
+	return nil
 }
 
 func (m *Message) UniqueVars() []*Var {
@@ -275,6 +718,69 @@ func (m *Message) UniqueVars() []*Var {
 	return vars
 }
 
+// allVars returns every var in m, including the selectors and the vars of nested variants.
+func (m *Message) allVars() []*Var {
+	vars := append([]*Var{}, m.Vars...)
+	if m.Selector != nil {
+		vars = append(vars, m.Selector)
+	}
+
+	for _, variant := range m.Variants {
+		vars = append(vars, variant.Message.allVars()...)
+	}
+
+	return vars
+}
+
+// replaceVars returns the format of m with the fmt verbs replaced by placeholder, spec contains the flags, width and precision of
+// the verb. An escaped %% is written as %% when escapePercent is set and as % otherwise.
+func (m *Message) replaceVars(escapePercent bool, placeholder func(v *Var, spec string) string) string {
+	return m.replaceText(func(text string) string {
+		if escapePercent {
+			return strings.ReplaceAll(text, "%", "%%")
+		}
+
+		return text
+	}, placeholder)
+}
+
+// replaceText returns the format of m with the text between the fmt verbs replaced by literal and the verbs replaced by
+// placeholder, see replaceVars. The text that literal gets contains an escaped %% as %.
+func (m *Message) replaceText(literal func(text string) string, placeholder func(v *Var, spec string) string) string {
+	var b, text strings.Builder
+	format := m.Message
+	n := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			text.WriteByte(format[i])
+			continue
+		}
+
+		if strings.HasPrefix(format[i:], "%%") {
+			text.WriteString("%")
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(format) && strings.IndexByte("+-# 0123456789.", format[end]) >= 0 {
+			end++
+		}
+
+		b.WriteString(literal(text.String()))
+		text.Reset()
+
+		b.WriteString(placeholder(m.Vars[n], format[i+1:end]))
+		n++
+		i = end
+	}
+
+	b.WriteString(literal(text.String()))
+
+	return b.String()
+}
+
 func (m *Message) HasType(t VarType) bool {
 	for _, v := range m.Vars {
 		if v.Type == t {
@@ -298,6 +804,158 @@ func (m *Message) Var(name string) *Var {
 type Var struct {
 	Name string
 	Type VarType
+	// Layout is the time layout of VarTypeTime vars.
+	Layout string
+	// GoType is the declared Go type of the var like int64 or ids.UserID, it is empty when the type is not declared.
+	// Ints and floats without a Go type become type parameters of the generated function.
+	GoType string
+	// Import is the import path of the package of GoType, it is empty for builtin types.
+	Import string
+}
+
+// importName returns the package name of the declared type of v, the name of Import in the generated code.
+func (v *Var) importName() string {
+	name, _, _ := strings.Cut(v.GoType, ".")
+	return name
+}
+
+// importNames returns the package name of every declared import path.
+func (c Messages) importNames() map[string]string {
+	names := make(map[string]string)
+	for _, l := range c.Messages {
+		for _, m := range l.messages() {
+			for _, v := range m.Vars {
+				if v.Import != "" {
+					names[v.Import] = v.importName()
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+// declare sets the Go type of v from a builtin type like int64 or a type in a package like example.com/ids.UserID.
+// The package name is the last element of the path, a package with another name is declared with its name like
+// yaml=gopkg.in/yaml.v3.Node.
+func (v *Var) declare(goType string) error {
+	name, goType, hasName := strings.Cut(goType, "=")
+	if !hasName {
+		goType = name
+		name = ""
+	}
+
+	i := strings.LastIndex(goType, ".")
+	if i < 0 {
+		varType, ok := builtinTypes[goType]
+		if !ok {
+			return fmt.Errorf("%w: %q is not a builtin type", ErrGoTypeInvalid, goType)
+		}
+
+		if varType != v.Type && v.Type != VarTypeAny {
+			return fmt.Errorf("%w: %q can't be used for a %s", ErrGoTypeInvalid, goType, v.Type)
+		}
+
+		v.GoType = goType
+		return nil
+	}
+
+	path, typeName := goType[:i], goType[i+1:]
+	if !goIdentRe.MatchString(typeName) || (hasName && !goIdentRe.MatchString(name)) {
+		return fmt.Errorf("%w: %q", ErrGoTypeInvalid, goType)
+	}
+
+	// The generated code formats times with their Format method, times and durations can't be other types.
+	if want, ok := timeTypes[v.Type]; ok && (path != "time" || typeName != want) {
+		return fmt.Errorf("%w: %q can't be used for a %s, use time.%s", ErrGoTypeInvalid, goType, v.Type, want)
+	}
+
+	if !hasName {
+		name = path[strings.LastIndex(path, "/")+1:]
+
+		// Paths like gopkg.in/yaml.v3, example.com/ids/v2 and example.com/go-ids don't end in the name of the package.
+		if !goIdentRe.MatchString(name) || majorVersionRe.MatchString(name) {
+			return fmt.Errorf("%w: the package name of %q is unknown, declare it like name=%s", ErrGoTypeInvalid, path, goType)
+		}
+	}
+
+	v.GoType = name + "." + typeName
+	v.Import = path
+
+	return nil
+}
+
+var (
+	// goIdentRe matches a Go identifier.
+	goIdentRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	// majorVersionRe matches the major version suffix of a module path.
+	majorVersionRe = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// timeTypes contains the type in the time package of the var types that can only be declared as that type.
+var timeTypes = map[VarType]string{
+	VarTypeTime:     "Time",
+	VarTypeDuration: "Duration",
+}
+
+// builtinTypes contains the builtin Go types that can be declared for a var by the var type they fit.
+var builtinTypes = map[string]VarType{
+	"string":  VarTypeString,
+	"int":     VarTypeInt,
+	"int8":    VarTypeInt,
+	"int16":   VarTypeInt,
+	"int32":   VarTypeInt,
+	"int64":   VarTypeInt,
+	"uint":    VarTypeInt,
+	"uint8":   VarTypeInt,
+	"uint16":  VarTypeInt,
+	"uint32":  VarTypeInt,
+	"uint64":  VarTypeInt,
+	"float32": VarTypeFloat,
+	"float64": VarTypeFloat,
+	"bool":    VarTypeBool,
+	"error":   VarTypeError,
+	"any":     VarTypeAny,
+}
+
+// resolveGoTypes gives every occurrence of a var the Go type that is declared on one of them.
+// Nothing is changed when a var is declared with different Go types.
+func resolveGoTypes(messages ...*Message) error {
+	declared, err := declaredGoTypes(messages...)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range messages {
+		for _, v := range m.allVars() {
+			if d, ok := declared[v.Name]; ok {
+				v.GoType = d.GoType
+				v.Import = d.Import
+			}
+		}
+	}
+
+	return nil
+}
+
+// declaredGoTypes returns the var with a declared Go type by name.
+func declaredGoTypes(messages ...*Message) (map[string]*Var, error) {
+	declared := make(map[string]*Var)
+	for _, m := range messages {
+		for _, v := range m.allVars() {
+			if v.GoType == "" {
+				continue
+			}
+
+			if d, ok := declared[v.Name]; ok && d.GoType != v.GoType {
+				return nil, fmt.Errorf("variable %q has type %q and %q: %w", v.Name, d.GoType, v.GoType, ErrVariableTypeMix)
+			}
+
+			declared[v.Name] = v
+		}
+	}
+
+	return declared, nil
 }
 
 type VarType string
@@ -306,11 +964,19 @@ var (
 	VarTypeString VarType = "string"
 	VarTypeInt    VarType = "int"
 	VarTypeFloat  VarType = "float"
+	VarTypeBool   VarType = "bool"
+	// VarTypeTime is a time.Time, it is formatted with the Layout of the var.
+	VarTypeTime     VarType = "time"
+	VarTypeDuration VarType = "duration"
+	// VarTypeAny accepts any value, including fmt.Stringers.
+	VarTypeAny   VarType = "any"
+	VarTypeError VarType = "error"
 )
 
 // reservedKeywords contains all the reserved keywords. Variables and functions cannot have these names.
+// The packages used by the generated code are reserved as well, a var with the same name would shadow them.
 var reservedKeywords = []string{
-	"ctx",
+	"ctx", "fmt", "time", "staticmessages",
 	"break", "default", "func", "interface", "select",
 	"case", "defer", "go", "map", "struct",
 	"chan", "else", "goto", "package", "switch",
@@ -318,6 +984,10 @@ var reservedKeywords = []string{
 	"continue", "for", "import", "return", "var",
 }
 
+// reservedIdentifiers contains the identifiers of which the generated function has the name of a function of the file,
+// like UsersLocales.
+var reservedIdentifiers = []string{"Locales", "MessageLocales"}
+
 // isReservedKeyword checks if the given word is a reserved keyword in Go.
 func isReservedKeyword(word string) bool {
 	for _, keyword := range reservedKeywords {
@@ -329,6 +999,7 @@ func isReservedKeyword(word string) bool {
 }
 
 // varTypesConsistent checks if the variable types are consistent between the compared and target message.
+// The vars of messages with variants include the selector and the vars of every variant, so all forms are checked.
 func varTypesConsistent(comp *Message, target *Message) error {
 	for _, compVar := range comp.Vars {
 		for _, targetVar := range target.Vars {
@@ -60,6 +60,26 @@ func TestLocalizedMessage(t *testing.T) {
 		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
 	})
 
+	t.Run("declared types between default and translation", func(t *testing.T) {
+		msg, err := staticmessages.ParseMessage("User %(ID:int64)d")
+		require.NoError(t, err)
+
+		c, err := staticmessages.NewLocalizedMessage("Foo", msg)
+		require.NoError(t, err)
+
+		tr, err := staticmessages.ParseMessage("Gebruiker %(ID)d")
+		require.NoError(t, err)
+
+		require.NoError(t, c.AddTranslation("nl", tr))
+		require.Equal(t, "int64", tr.Vars[0].GoType)
+
+		tr, err = staticmessages.ParseMessage("Utilisateur %(ID:int32)d")
+		require.NoError(t, err)
+
+		err = c.AddTranslation("fr", tr)
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	})
+
 	t.Run("invalid vars between 2 translations", func(t *testing.T) {
 		msg, err := staticmessages.ParseMessage("Hello, world!")
 		require.NoError(t, err)
@@ -145,7 +165,7 @@ func TestParseMessage(t *testing.T) {
 
 	t.Run("reserved keyword var", func(t *testing.T) {
 		for _, reserved := range []string{
-			"ctx",
+			"ctx", "fmt", "time", "staticmessages",
 			"break", "default", "func", "interface", "select",
 			"case", "defer", "go", "map", "struct",
 			"chan", "else", "goto", "package", "switch",
@@ -176,6 +196,78 @@ func TestParseMessage(t *testing.T) {
 		require.Len(t, msg.UniqueVars(), 2)
 	})
 
+	t.Run("parse var types", func(t *testing.T) {
+		msg, err := staticmessages.ParseMessage("%(ok)b %(at)t %(took)D %(id)v %(cause)e")
+		require.NoError(t, err)
+
+		require.Equal(t, "%t %s %s %v %v", msg.Message)
+		require.Equal(t, []*staticmessages.Var{
+			{Name: "ok", Type: staticmessages.VarTypeBool},
+			{Name: "at", Type: staticmessages.VarTypeTime, Layout: "2006-01-02 15:04:05"},
+			{Name: "took", Type: staticmessages.VarTypeDuration},
+			{Name: "id", Type: staticmessages.VarTypeAny},
+			{Name: "cause", Type: staticmessages.VarTypeError},
+		}, msg.Vars)
+	})
+
+	t.Run("declared types", func(t *testing.T) {
+		msg, err := staticmessages.ParseMessage("User %(ID:int64)d of %(org:example.com/orgs.Name)s, %(ID)d")
+		require.NoError(t, err)
+
+		require.Equal(t, "User %d of %s, %d", msg.Message)
+		require.Equal(t, &staticmessages.Var{Name: "ID", Type: staticmessages.VarTypeInt, GoType: "int64"}, msg.Vars[0])
+		require.Equal(t, &staticmessages.Var{Name: "org", Type: staticmessages.VarTypeString, GoType: "orgs.Name", Import: "example.com/orgs"}, msg.Vars[1])
+		// The declaration applies to every occurrence of the var.
+		require.Equal(t, "int64", msg.Vars[2].GoType)
+	})
+
+	t.Run("declared package names", func(t *testing.T) {
+		msg, err := staticmessages.ParseMessage("%(doc:yaml=gopkg.in/yaml.v3.Node)v of %(ID:ids=example.com/my-ids.ID)v")
+		require.NoError(t, err)
+
+		require.Equal(t, &staticmessages.Var{Name: "doc", Type: staticmessages.VarTypeAny, GoType: "yaml.Node", Import: "gopkg.in/yaml.v3"}, msg.Vars[0])
+		require.Equal(t, &staticmessages.Var{Name: "ID", Type: staticmessages.VarTypeAny, GoType: "ids.ID", Import: "example.com/my-ids"}, msg.Vars[1])
+	})
+
+	t.Run("invalid declared types", func(t *testing.T) {
+		for _, c := range []struct {
+			raw      string
+			expected error
+		}{
+			{raw: "%(ID:string)d", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(ID:int128)d", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(ID:example.com/my-ids.ID)v", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(doc:gopkg.in/yaml.v3.Node)v", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(ID:example.com/ids/v2.ID)v", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(ID:my-ids=example.com/my-ids.ID)v", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(at:example.com/dates.Time)t", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(at:time.Duration)t", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(took:example.com/dates.Duration)D", expected: staticmessages.ErrGoTypeInvalid},
+			{raw: "%(ID:int64)d %(ID:int32)d", expected: staticmessages.ErrVariableTypeMix},
+		} {
+			_, err := staticmessages.ParseMessage(c.raw)
+			require.ErrorIs(t, err, c.expected, c.raw)
+		}
+	})
+
+	t.Run("literal percent", func(t *testing.T) {
+		msg, err := staticmessages.ParseMessage("100% sure, %(n)d%% done, 50 %")
+		require.NoError(t, err)
+		require.Equal(t, "100%% sure, %d%% done, 50 %%", msg.Message)
+	})
+
+	t.Run("bare verb", func(t *testing.T) {
+		for _, raw := range []string{"Hello %s", "Hello %(user)s, you have %d messages", "Total %.2f", "Total %-5v"} {
+			_, err := staticmessages.ParseMessage(raw)
+			require.ErrorIs(t, err, staticmessages.ErrBareVerb, raw)
+		}
+	})
+
+	t.Run("incomplete placeholder", func(t *testing.T) {
+		_, err := staticmessages.ParseMessage("Hello %(user)!")
+		require.ErrorIs(t, err, staticmessages.ErrUnsupportedFormat)
+	})
+
 	floatingPointCases := []struct {
 		message     string
 		expected    string
@@ -219,3 +311,152 @@ func TestParseMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPluralMessage(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		msg, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+			newVariant(t, "=0", "No items in %(folder)s"),
+			newVariant(t, "one", "One item in %(folder)s"),
+			newVariant(t, "other", "%(count)d items in %(folder)s"),
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "count", msg.Selector.Name)
+		require.Equal(t, staticmessages.VarTypeInt, msg.Selector.Type)
+		require.True(t, msg.HasPlural())
+		require.Len(t, msg.UniqueVars(), 2)
+		require.Len(t, msg.ExactVariants(), 1)
+		require.Equal(t, "0", msg.ExactVariants()[0].Exact())
+		require.Len(t, msg.CategoryVariants(), 1)
+		require.Equal(t, "other", msg.OtherVariant().Key)
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		_, err := staticmessages.NewPluralMessage("count1", []*staticmessages.Variant{newVariant(t, "other", "Items")})
+		require.ErrorIs(t, err, staticmessages.ErrSelectorInvalid)
+
+		_, err = staticmessages.NewPluralMessage("type", []*staticmessages.Variant{newVariant(t, "other", "Items")})
+		require.ErrorIs(t, err, staticmessages.ErrReservedKeyword)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		for _, key := range []string{"several", "=", "=-1", "=a"} {
+			_, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+				newVariant(t, key, "Items"),
+				newVariant(t, "other", "Items"),
+			})
+			require.ErrorIs(t, err, staticmessages.ErrPluralKeyInvalid, key)
+		}
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		_, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+			newVariant(t, "other", "Items"),
+			newVariant(t, "other", "Items"),
+		})
+		require.ErrorIs(t, err, staticmessages.ErrDuplicateVariant)
+	})
+
+	t.Run("other missing", func(t *testing.T) {
+		_, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{newVariant(t, "one", "One item")})
+		require.ErrorIs(t, err, staticmessages.ErrOtherVariantMissing)
+	})
+
+	t.Run("selector used as string", func(t *testing.T) {
+		_, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{newVariant(t, "other", "%(count)s items")})
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	})
+
+	t.Run("var type differs between variants", func(t *testing.T) {
+		_, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+			newVariant(t, "one", "One item in %(folder)s"),
+			newVariant(t, "other", "Items in %(folder)d"),
+		})
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	})
+
+	t.Run("var type differs from translation", func(t *testing.T) {
+		msg, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+			newVariant(t, "one", "One item in %(folder)s"),
+			newVariant(t, "other", "%(count)d items in %(folder)s"),
+		})
+		require.NoError(t, err)
+
+		loc, err := staticmessages.NewLocalizedMessage("Items", msg)
+		require.NoError(t, err)
+
+		tr, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+			newVariant(t, "one", "Een item in %(folder)d"),
+			newVariant(t, "other", "%(count)d items in %(folder)d"),
+		})
+		require.NoError(t, err)
+
+		err = loc.AddTranslation("nl", tr)
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	})
+}
+
+func TestNewSelectMessage(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		msg, err := staticmessages.NewSelectMessage("gender", []*staticmessages.Variant{
+			newVariant(t, "male", "He updated %(document)s"),
+			newVariant(t, "female", "She updated %(document)s"),
+			newVariant(t, "other", "They updated %(document)s"),
+		})
+		require.NoError(t, err)
+
+		require.True(t, msg.IsSelect())
+		require.False(t, msg.IsPlural())
+		require.Equal(t, staticmessages.VarTypeString, msg.Selector.Type)
+		require.Len(t, msg.UniqueVars(), 2)
+		require.Len(t, msg.CategoryVariants(), 2)
+		require.Equal(t, "other", msg.OtherVariant().Key)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		for _, key := range []string{"", "_male"} {
+			_, err := staticmessages.NewSelectMessage("gender", []*staticmessages.Variant{
+				newVariant(t, key, "He"),
+				newVariant(t, "other", "They"),
+			})
+			require.ErrorIs(t, err, staticmessages.ErrSelectKeyInvalid)
+		}
+	})
+
+	t.Run("other missing", func(t *testing.T) {
+		_, err := staticmessages.NewSelectMessage("gender", []*staticmessages.Variant{newVariant(t, "male", "He")})
+		require.ErrorIs(t, err, staticmessages.ErrOtherVariantMissing)
+	})
+
+	t.Run("selector used as int", func(t *testing.T) {
+		_, err := staticmessages.NewSelectMessage("gender", []*staticmessages.Variant{newVariant(t, "other", "%(gender)d")})
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	})
+
+	t.Run("selector type differs from translation", func(t *testing.T) {
+		msg, err := staticmessages.NewSelectMessage("role", []*staticmessages.Variant{
+			newVariant(t, "admin", "An admin updated the document"),
+			newVariant(t, "other", "Someone updated the document"),
+		})
+		require.NoError(t, err)
+
+		loc, err := staticmessages.NewLocalizedMessage("Updated", msg)
+		require.NoError(t, err)
+
+		tr, err := staticmessages.ParseMessage("Document bijgewerkt door rol %(role)d")
+		require.NoError(t, err)
+
+		err = loc.AddTranslation("nl", tr)
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	})
+}
+
+// newVariant returns a variant with the parsed raw message.
+func newVariant(t *testing.T, key, raw string) *staticmessages.Variant {
+	t.Helper()
+
+	msg, err := staticmessages.ParseMessage(raw)
+	require.NoError(t, err)
+
+	return &staticmessages.Variant{Key: key, Message: msg}
+}
@@ -0,0 +1,140 @@
+package staticmessages
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// mobileResource is a string resource of a message in the Android and iOS exports.
+type mobileResource struct {
+	name string
+	l    *LocalizedMessage
+	// keys are the variant keys of messages that are split into a resource per variant.
+	keys []string
+	// plural is set for plurals without nested variants, they are exported as Android plurals and iOS stringsdict entries.
+	plural bool
+}
+
+// resourceNameRe matches the characters that can't be used in a resource name.
+var resourceNameRe = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// mobileResources returns the resources of msg. The names start with the name of msg like the generated Go functions,
+// so the resources of different files don't collide in an app. Plurals with nested variants and selects get a resource
+// for every variant, named after the identifier and the keys like UsersRole_admin.
+func mobileResources(msg *Messages) []*mobileResource {
+	resources := make([]*mobileResource, 0)
+	for _, l := range msg.Messages {
+		if isFlatPlural(l.Default) {
+			resources = append(resources, &mobileResource{name: msg.Name + l.Identifier, l: l, plural: true})
+			continue
+		}
+
+		for _, keys := range variantPaths(l.Default, nil) {
+			name := msg.Name + l.Identifier
+			for _, key := range keys {
+				name += "_" + resourceNameRe.ReplaceAllString(key, "_")
+			}
+
+			resources = append(resources, &mobileResource{name: name, l: l, keys: keys})
+		}
+	}
+
+	return resources
+}
+
+// message returns the message of the resource in locale, an empty locale returns the default message.
+// Nil is returned when the message isn't translated.
+func (r *mobileResource) message(locale string) *Message {
+	if locale == "" {
+		return leafMessage(r.l.Default, r.keys)
+	}
+
+	if msg := r.l.Translation(locale); msg != nil {
+		return leafMessage(msg, r.keys)
+	}
+
+	return nil
+}
+
+// pluralQuantities returns the plural categories of locale with the text of msg for every category, exact variants are left out.
+func pluralQuantities(msg *Message, locale string) ([]string, []*Message) {
+	categories := PluralCategories(locale)
+
+	// Both platforms require the other category, even for languages that don't use it for integers.
+	if !contains(categories, "other") {
+		categories = append(categories, "other")
+	}
+
+	messages := make([]*Message, 0, len(categories))
+	for _, category := range categories {
+		messages = append(messages, leafMessage(msg, []string{category}))
+	}
+
+	return categories, messages
+}
+
+// isFlatPlural reports if m is a plural of which none of the variants contain variants.
+func isFlatPlural(m *Message) bool {
+	if !m.IsPlural() {
+		return false
+	}
+
+	for _, v := range m.Variants {
+		if len(v.Message.Variants) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// variantPaths returns the keys that lead to every text of m, a message without variants has a single empty path.
+func variantPaths(m *Message, keys []string) [][]string {
+	if len(m.Variants) == 0 {
+		return [][]string{keys}
+	}
+
+	paths := make([][]string, 0)
+	for _, v := range m.Variants {
+		paths = append(paths, variantPaths(v.Message, append(keys[:len(keys):len(keys)], v.Key))...)
+	}
+
+	return paths
+}
+
+// leafMessage follows keys through the variants of m, the other variant is used for keys that m doesn't contain.
+// A message without variants is the text of every key.
+func leafMessage(m *Message, keys []string) *Message {
+	for _, key := range keys {
+		if len(m.Variants) == 0 {
+			return m
+		}
+
+		v := m.variant(key)
+		if v == nil {
+			v = m.OtherVariant()
+		}
+
+		m = v.Message
+	}
+
+	return m
+}
+
+// positionalFormat rewrites the fmt verbs of m into positional placeholders like %1$s. The position is the index of the var
+// in vars and conversion returns the conversion of a var. Percent signs are only escaped when the message has vars, the apps
+// only format messages with arguments.
+func positionalFormat(m *Message, vars []*Var, conversion func(v *Var) string) string {
+	return m.replaceVars(len(vars) > 0, func(v *Var, spec string) string {
+		position := 0
+		for j, u := range vars {
+			if u.Name == v.Name {
+				position = j + 1
+				break
+			}
+		}
+
+		// The flags, width and precision of the verb are kept.
+		return "%" + strconv.Itoa(position) + "$" + spec + conversion(v)
+	})
+}
@@ -0,0 +1,57 @@
+package staticmessages_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+const mobileSource = `# Shown when a user cannot be found.
+NotFound:
+  default: User %(ID)d not found, 100% sure
+  nl: Gebruiker %(ID)d niet gevonden
+Files:
+  default:
+    _plural: count
+    =0: No files in %(folder)s
+    one: One file in %(folder)s
+    other: "%(count)d files in %(folder)s"
+  pl: "%(folder)s: %(count)d plików"
+Role:
+  default:
+    _select: role
+    admin: Admin "%(name)s" & co
+    other: User @%(name)s with %(score).1f
+Ratio:
+  default: 100%
+`
+
+func TestWriteAndroid(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(mobileSource))
+	require.NoError(t, err)
+
+	for _, locale := range []string{"", "pl"} {
+		var buf bytes.Buffer
+		require.NoError(t, staticmessages.WriteAndroid(messages, "en", locale, &buf))
+
+		compareGolden(t, buf.Bytes(), "android.golden_"+staticmessages.AndroidResourceDir(locale))
+	}
+
+	require.Equal(t, "values-pt-rBR", staticmessages.AndroidResourceDir("pt-BR"))
+}
+
+func TestWriteIOS(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(mobileSource))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, staticmessages.WriteIOSStrings(messages, "", &buf))
+	compareGolden(t, buf.Bytes(), "ios.golden_strings")
+
+	buf.Reset()
+	require.NoError(t, staticmessages.WriteIOSStringsDict(messages, "en", "pl", &buf))
+	compareGolden(t, buf.Bytes(), "ios.golden_stringsdict_pl")
+}
@@ -3,7 +3,14 @@ package staticmessages
 import (
 	"errors"
 	"fmt"
+	"go/token"
 	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -13,45 +20,284 @@ import (
 var (
 	ErrYamlNameInvalid       = errors.New("yml name invalid")
 	ErrYamlDefinitionInvalid = errors.New("yml definition is invalid")
+
+	// yamlLineRe matches the line number in errors returned by the yaml decoder.
+	yamlLineRe = regexp.MustCompile(`^yaml: line ([0-9]+): `)
 )
 
+// ParseError is an error in a messages file, it points to the node that caused the error.
+type ParseError struct {
+	// File is the name of the parsed file, it is empty when the messages are not parsed from a file.
+	File   string
+	Line   int
+	Column int
+	// Identifier and Locale are set when the error is part of a message.
+	Identifier string
+	Locale     string
+	// Err wraps the sentinel errors like ErrYamlDefinitionInvalid.
+	Err error
+}
+
+// Error returns the error in the file:line:col: message format.
+func (e *ParseError) Error() string {
+	var b strings.Builder
+
+	if e.File != "" {
+		b.WriteString(e.File + ":")
+	}
+
+	if e.Line > 0 {
+		b.WriteString(strconv.Itoa(e.Line) + ":")
+
+		if e.Column > 0 {
+			b.WriteString(strconv.Itoa(e.Column) + ":")
+		}
+	}
+
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+
+	if e.Identifier != "" {
+		b.WriteString(e.Identifier)
+
+		if e.Locale != "" {
+			b.WriteString(" (" + e.Locale + ")")
+		}
+
+		b.WriteString(": ")
+	}
+
+	b.WriteString(e.Err.Error())
+
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors contains every error found while parsing, parsing continues after an error so all of them are reported at once.
+type ParseErrors []*ParseError
+
+// Error returns every error on it's own line.
+func (e ParseErrors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
 // Parse parses yml messages from r.
+// All errors in r are returned as ParseErrors.
 func Parse(name string, r io.Reader) (*Messages, error) {
+	return parse("", name, r)
+}
+
+// ParseFile parses the messages in the file at path, files with the .json extension are parsed as JSON and all other files as yml.
+// The name of the messages is the file name without the extension.
+func ParseFile(path string) (*Messages, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	base := filepath.Base(path)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+
+	if strings.EqualFold(filepath.Ext(base), ".json") {
+		return parseJSON(path, name, f)
+	}
+
+	return parse(path, name, f)
+}
+
+// parser parses a single messages file.
+type parser struct {
+	file   string
+	syntax Syntax
+	errs   ParseErrors
+	// imports contains the import path of the package names in the file.
+	imports map[string]string
+}
+
+// errorf records a ParseError at the position of node.
+func (p *parser) errorf(node *yaml.Node, identifier, locale string, format string, args ...any) {
+	p.errs = append(p.errs, &ParseError{
+		File:       p.file,
+		Line:       node.Line,
+		Column:     node.Column,
+		Identifier: identifier,
+		Locale:     locale,
+		Err:        fmt.Errorf(format, args...),
+	})
+}
+
+func parse(file, name string, r io.Reader) (*Messages, error) {
 	if len(name) == 0 {
 		return nil, ErrYamlNameInvalid
 	}
 
-	// Capitalize the first letter of the name.
-	ru, size := utf8.DecodeRuneInString(name)
-	name = string(unicode.ToUpper(ru)) + name[size:]
+	node, err := decode(file, r)
+	if err != nil {
+		return nil, err
+	}
 
+	return parseNode(file, name, node)
+}
+
+// decode decodes the yml document in r into a node.
+func decode(file string, r io.Reader) (yaml.Node, error) {
 	var node yaml.Node
 	decoder := yaml.NewDecoder(r)
 	if err := decoder.Decode(&node); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrYamlDefinitionInvalid, err)
+		perr := &ParseError{File: file, Err: fmt.Errorf("%w: %v", ErrYamlDefinitionInvalid, err)}
+		if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+			perr.Line, _ = strconv.Atoi(m[1])
+		}
+
+		return node, ParseErrors{perr}
+	}
+
+	return node, nil
+}
+
+// parseNode parses the messages in the decoded document node.
+func parseNode(file, name string, node yaml.Node) (*Messages, error) {
+	if len(name) == 0 {
+		return nil, ErrYamlNameInvalid
+	}
+
+	// Capitalize the first letter of the name.
+	ru, size := utf8.DecodeRuneInString(name)
+	name = string(unicode.ToUpper(ru)) + name[size:]
+
+	p := &parser{file: file}
+
+	root := p.root(&node)
+	if root == nil {
+		return nil, p.errs
+	}
+
+	messages := &Messages{
+		Name:     name,
+		Messages: make([]*LocalizedMessage, 0),
+	}
+
+	p.parseSyntax(root)
+	messages.Package = p.parsePackage(root)
+
+	p.parseGroup(root, "", messages)
+
+	if len(p.errs) > 0 {
+		return nil, p.errs
+	}
+
+	return messages, nil
+}
+
+// root returns the top level mapping of a document, nil is returned if the document doesn't contain a mapping.
+// Aliases and merge keys are resolved, see resolve.
+func (p *parser) root(node *yaml.Node) *yaml.Node {
+	p.checkMerges(node)
+	if len(p.errs) > 0 {
+		return nil
 	}
 
+	node = resolve(node)
+
 	if node.Kind == yaml.DocumentNode {
 		if len(node.Content) == 0 {
-			return nil, fmt.Errorf("%w: expected yaml.MappingNode got Document node without content", ErrYamlDefinitionInvalid)
+			p.errorf(node, "", "", "%w: expected yaml.MappingNode got Document node without content", ErrYamlDefinitionInvalid)
+			return nil
 		}
 
 		if len(node.Content) > 1 {
-			return nil, fmt.Errorf("%w: expected yaml.MappingNode got Document node with more then 1 child", ErrYamlDefinitionInvalid)
+			p.errorf(node, "", "", "%w: expected yaml.MappingNode got Document node with more then 1 child", ErrYamlDefinitionInvalid)
+			return nil
 		}
 
-		node = *node.Content[0]
+		node = node.Content[0]
 	}
 
 	if node.Kind != yaml.MappingNode {
-		return nil, fmt.Errorf("%w: expected yaml.MappingNode got: %d", ErrYamlDefinitionInvalid, node.Kind)
+		p.errorf(node, "", "", "%w: expected yaml.MappingNode got %s", ErrYamlDefinitionInvalid, kindName(node.Kind))
+		return nil
 	}
 
-	messages := &Messages{
-		Name:     name,
-		Messages: make([]*LocalizedMessage, 0),
+	return node
+}
+
+// parseSyntax sets the syntax from the _syntax key at the top level of the file, messages use the printf syntax by default.
+// A message can set its own syntax with a _syntax key in its spec.
+//
+//	_syntax: icu
+//	HelloUser:
+//	  default: Hello {user}!
+func (p *parser) parseSyntax(node *yaml.Node) {
+	for i := 0; (i + 1) < len(node.Content); i += 2 {
+		if node.Content[i].Value == "_syntax" {
+			p.syntax = p.syntaxValue(node.Content[i+1], "")
+		}
+	}
+}
+
+// syntaxValue returns the syntax of a _syntax value, the syntax of the file is returned when the value is invalid.
+func (p *parser) syntaxValue(value *yaml.Node, identifier string) Syntax {
+	switch syntax := Syntax(value.Value); syntax {
+	case SyntaxPrintf, SyntaxICU:
+		return syntax
 	}
 
+	p.errorf(value, identifier, "", "%w: _syntax must be %q or %q, got %q", ErrYamlDefinitionInvalid, SyntaxPrintf, SyntaxICU, value.Value)
+
+	return p.syntax
+}
+
+// parsePackage returns the Go package from the _package key at the top level of the file, it is empty without _package.
+//
+//	_package: billing
+func (p *parser) parsePackage(node *yaml.Node) string {
+	for i := 0; (i + 1) < len(node.Content); i += 2 {
+		if node.Content[i].Value != "_package" {
+			continue
+		}
+
+		value := node.Content[i+1]
+		if value.Kind != yaml.ScalarNode || !token.IsIdentifier(value.Value) {
+			p.errorf(value, "", "", "%w: _package must be a valid Go package name, got %q", ErrYamlDefinitionInvalid, value.Value)
+			return ""
+		}
+
+		return value.Value
+	}
+
+	return ""
+}
+
+// parseGroup parses the messages in a group, the top level of a file is a group as well.
+// Groups can be nested, the identifiers of the messages in a group are prefixed with the group identifier.
+//
+// The following yaml:
+//
+//	User:
+//	  NotFound:
+//	    default: User not found
+//
+// Results into a message with the identifier UserNotFound.
+func (p *parser) parseGroup(node *yaml.Node, prefix string, messages *Messages) {
 	// We parse the yaml manually into a yaml.Node to maintain the ordering of the fields as defined in r.
 	// If we would use a map the ordering is not guaranteed.
 	//
@@ -77,74 +323,361 @@ func Parse(name string, r io.Reader) (*Messages, error) {
 		spec := node.Content[i+1]
 
 		if identifier.Kind != yaml.ScalarNode {
-			return nil, fmt.Errorf("%w: expected yaml.ScalarNode for node %#v", ErrYamlDefinitionInvalid, identifier)
+			p.errorf(identifier, "", "", "%w: expected yaml.ScalarNode got %s", ErrYamlDefinitionInvalid, kindName(identifier.Kind))
+			continue
+		}
+
+		// File options are handled by parseSyntax and parsePackage, _shared contains anchors for aliases.
+		if prefix == "" && (identifier.Value == "_syntax" || identifier.Value == "_package" || identifier.Value == "_shared") {
+			continue
+		}
+
+		name := prefix + identifier.Value
+
+		if prefix != "" && !identifierRe.MatchString(identifier.Value) {
+			p.errorf(identifier, name, "", "%w: group %q can only contain identifiers: %w", ErrYamlDefinitionInvalid, prefix, ErrIdentifierInvalid)
+			continue
 		}
 
 		if spec.Kind != yaml.MappingNode {
-			return nil, fmt.Errorf("%w: expected yaml.MappingNode for node %#v", ErrYamlDefinitionInvalid, identifier)
+			p.errorf(spec, name, "", "%w: expected yaml.MappingNode got %s", ErrYamlDefinitionInvalid, kindName(spec.Kind))
+			continue
 		}
 
-		loc, err := parseMessage(identifier.Value, spec)
-		if err != nil {
-			return nil, err
+		if isGroup(spec) {
+			p.parseGroup(spec, name, messages)
+			continue
+		}
+
+		loc := p.parseMessage(identifier, name, spec)
+		if loc == nil {
+			continue
 		}
 
-		messages.Messages = append(messages.Messages, loc)
+		// Messages in different groups can end up with the same identifier, User.NotFound and UserNotFound for example.
+		if err := messages.Add(loc); err != nil {
+			p.errorf(identifier, name, "", "%w: %w", ErrYamlDefinitionInvalid, err)
+			continue
+		}
+
+		p.checkImports(identifier, loc)
 	}
+}
 
-	return messages, nil
+// generatedImports contains the packages the generated code imports by name.
+var generatedImports = map[string]string{
+	"context":        "context",
+	"errors":         "errors",
+	"fmt":            "fmt",
+	"time":           "time",
+	"staticmessages": "github.com/wvell/staticmessages",
+}
+
+// checkImports reports the declared types of loc of which the package name is used by another package in the file.
+func (p *parser) checkImports(identifier *yaml.Node, loc *LocalizedMessage) {
+	if p.imports == nil {
+		p.imports = maps.Clone(generatedImports)
+	}
+
+	for _, m := range loc.messages() {
+		for _, v := range m.Vars {
+			if v.Import == "" {
+				continue
+			}
+
+			name := v.importName()
+			if path, ok := p.imports[name]; ok && path != v.Import {
+				p.errorf(identifier, loc.Identifier, "", "%w: the package name %s of %q is used by %q, declare another name like x=%s.%s",
+					ErrGoTypeInvalid, name, v.Import, path, v.Import, strings.TrimPrefix(v.GoType, name+"."))
+				continue
+			}
+
+			p.imports[name] = v.Import
+		}
+	}
+}
+
+// isGroup checks if spec is a group of messages instead of a message spec.
+// Message specs contain locales and reserved keys, which never start with an uppercase letter like identifiers.
+func isGroup(spec *yaml.Node) bool {
+	for i := 0; i < len(spec.Content); i += 2 {
+		if identifierRe.MatchString(spec.Content[i].Value) {
+			return true
+		}
+	}
+
+	return false
 }
 
-func parseMessage(identifier string, spec *yaml.Node) (*LocalizedMessage, error) {
+// parseMessage parses the spec of a single message.
+//
+// Besides the locales a spec can contain reserved keys with documentation for developers and translators:
+//
+//	_description: Shown when the user does not exist.
+//	_context: Error page title.
+//	_example: User 5 not found
+//
+// Comments on the identifier are added to the description.
+// Errors are recorded on p, nil is returned if the message contains errors.
+func (p *parser) parseMessage(identifier *yaml.Node, name string, spec *yaml.Node) *LocalizedMessage {
 	var defaultMessage *Message
 	translations := make([]*Translation, 0)
-	var err error
+	// valueNodes contains the node of every translation by locale to point errors at the translation.
+	valueNodes := make(map[string]*yaml.Node)
+	errCount := len(p.errs)
+
+	description := make([]string, 0)
+	for _, comment := range []string{identifier.HeadComment, identifier.LineComment} {
+		if comment != "" {
+			description = append(description, stripComment(comment))
+		}
+	}
+
+	var context, example string
+	var vars *yaml.Node
+	hasDefault := false
+
+	syntax := p.syntax
+	for i := 0; (i + 1) < len(spec.Content); i += 2 {
+		if spec.Content[i].Value == "_syntax" {
+			syntax = p.syntaxValue(spec.Content[i+1], name)
+		}
+	}
 
 	for i := 0; (i + 1) < len(spec.Content); i += 2 {
 		key := spec.Content[i]
 		value := spec.Content[i+1]
 
 		if key.Kind != yaml.ScalarNode {
-			return nil, fmt.Errorf("%w: expected yaml.ScalarNode for node %#v", ErrYamlDefinitionInvalid, key)
+			p.errorf(key, name, "", "%w: expected yaml.ScalarNode got %s", ErrYamlDefinitionInvalid, kindName(key.Kind))
+			continue
 		}
 
-		if value.Kind != yaml.ScalarNode {
-			return nil, fmt.Errorf("%w: expected yaml.ScalarNode for node %#v", ErrYamlDefinitionInvalid, value)
+		if key.Value == "_vars" {
+			if value.Kind != yaml.MappingNode {
+				p.errorf(value, name, "", "%w: expected yaml.MappingNode for _vars got %s", ErrYamlDefinitionInvalid, kindName(value.Kind))
+				continue
+			}
+
+			vars = value
+			continue
 		}
 
-		if key.Value == "default" {
-			defaultMessage, err = ParseMessage(value.Value)
-			if err != nil {
-				return nil, fmt.Errorf("%w: error parsing node: %#v: %v", ErrYamlDefinitionInvalid, value, err)
+		if strings.HasPrefix(key.Value, "_") {
+			if value.Kind != yaml.ScalarNode {
+				p.errorf(value, name, "", "%w: expected yaml.ScalarNode for %s got %s", ErrYamlDefinitionInvalid, key.Value, kindName(value.Kind))
+				continue
+			}
+
+			switch key.Value {
+			case "_description":
+				description = append(description, value.Value)
+			case "_context":
+				context = value.Value
+			case "_example":
+				example = value.Value
+			case "_syntax":
+				// The syntax is parsed before the messages.
+			default:
+				p.errorf(key, name, "", "%w: unknown reserved key %q", ErrYamlDefinitionInvalid, key.Value)
 			}
+
+			continue
+		}
+
+		if key.Value == "default" {
+			hasDefault = true
+			defaultMessage = p.parseValue(value, name, "", syntax)
 		} else {
-			translation, err := ParseMessage(value.Value)
-			if err != nil {
-				return nil, fmt.Errorf("%w: error parsing node: %#v: %v", ErrYamlDefinitionInvalid, value, err)
+			translation := p.parseValue(value, name, key.Value, syntax)
+			if translation == nil {
+				continue
 			}
 
 			translations = append(translations, &Translation{
 				Locale:  key.Value,
 				Message: translation,
 			})
+			valueNodes[key.Value] = value
 		}
 	}
 
+	if !hasDefault {
+		p.errorf(spec, name, "", "%w: expected default message", ErrYamlDefinitionInvalid)
+	}
+
 	if defaultMessage == nil {
-		return nil, fmt.Errorf("%w: expected default message", ErrYamlDefinitionInvalid)
+		return nil
 	}
 
-	loc, err := NewLocalizedMessage(identifier, defaultMessage)
+	loc, err := NewLocalizedMessage(name, defaultMessage)
 	if err != nil {
-		return nil, fmt.Errorf("%w: error creating localized message: %v", ErrYamlDefinitionInvalid, err)
+		p.errorf(identifier, name, "", "%w: %w", ErrYamlDefinitionInvalid, err)
+		return nil
 	}
 
+	loc.Syntax = syntax
+	loc.Description = strings.Join(description, "\n")
+	loc.Context = context
+	loc.Example = example
+
 	for _, translation := range translations {
 		err = loc.AddTranslation(translation.Locale, translation.Message)
 		if err != nil {
-			return nil, fmt.Errorf("%w: error adding translation: %v", ErrYamlDefinitionInvalid, err)
+			p.errorf(valueNodes[translation.Locale], name, translation.Locale, "%w: %w", ErrYamlDefinitionInvalid, err)
+		}
+	}
+
+	if vars != nil {
+		p.parseVars(loc, vars)
+	}
+
+	if len(p.errs) > errCount {
+		return nil
+	}
+
+	return loc
+}
+
+// parseVars declares the Go types of the _vars mapping on loc.
+func (p *parser) parseVars(loc *LocalizedMessage, vars *yaml.Node) {
+	for i := 0; (i + 1) < len(vars.Content); i += 2 {
+		key := vars.Content[i]
+		value := vars.Content[i+1]
+
+		if key.Kind != yaml.ScalarNode || value.Kind != yaml.ScalarNode {
+			p.errorf(key, loc.Identifier, "", "%w: expected var name and type in _vars", ErrYamlDefinitionInvalid)
+			continue
+		}
+
+		if err := loc.DeclareVar(key.Value, value.Value); err != nil {
+			p.errorf(value, loc.Identifier, "", "%w: %w", ErrYamlDefinitionInvalid, err)
+		}
+	}
+}
+
+// parseValue parses a message from a scalar or from a mapping of plural or select variants.
+// Errors are recorded on p, nil is returned if the value contains errors.
+func (p *parser) parseValue(value *yaml.Node, identifier, locale string, syntax Syntax) *Message {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		msg, err := parseMessageSyntax(value.Value, syntax)
+		if err != nil {
+			p.errorf(value, identifier, locale, "%w: %w", ErrYamlDefinitionInvalid, err)
+			return nil
+		}
+
+		return msg
+	case yaml.MappingNode:
+		return p.parseVariants(value, identifier, locale, syntax)
+	}
+
+	p.errorf(value, identifier, locale, "%w: expected yaml.ScalarNode or yaml.MappingNode got %s", ErrYamlDefinitionInvalid, kindName(value.Kind))
+
+	return nil
+}
+
+// parseVariants parses a message with plural or select variants.
+//
+// The following yaml:
+//
+//	_plural: count
+//	=0: No items
+//	one: One item
+//	other: %(count)d items
+//
+// Results into a message pluralized on the integer var count. A _select key instead of _plural
+// picks the variant by the value of a string var.
+func (p *parser) parseVariants(spec *yaml.Node, identifier, locale string, syntax Syntax) *Message {
+	var selector, kind string
+	variants := make([]*Variant, 0)
+	errCount := len(p.errs)
+
+	for i := 0; (i + 1) < len(spec.Content); i += 2 {
+		key := spec.Content[i]
+		value := spec.Content[i+1]
+
+		if key.Kind != yaml.ScalarNode {
+			p.errorf(key, identifier, locale, "%w: expected yaml.ScalarNode got %s", ErrYamlDefinitionInvalid, kindName(key.Kind))
+			continue
+		}
+
+		if key.Value == "_plural" || key.Value == "_select" {
+			if value.Kind != yaml.ScalarNode {
+				p.errorf(value, identifier, locale, "%w: expected yaml.ScalarNode for %s got %s", ErrYamlDefinitionInvalid, key.Value, kindName(value.Kind))
+				continue
+			}
+
+			if kind != "" {
+				p.errorf(key, identifier, locale, "%w: expected either _plural or _select", ErrYamlDefinitionInvalid)
+				continue
+			}
+
+			kind = key.Value
+			selector = value.Value
+			continue
+		}
+
+		msg := p.parseValue(value, identifier, locale, syntax)
+		if msg == nil {
+			continue
 		}
+
+		variants = append(variants, &Variant{
+			Key:     key.Value,
+			Message: msg,
+		})
+	}
+
+	if kind == "" {
+		p.errorf(spec, identifier, locale, "%w: expected _plural or _select", ErrYamlDefinitionInvalid)
+	}
+
+	if len(p.errs) > errCount {
+		return nil
+	}
+
+	var msg *Message
+	var err error
+
+	if kind == "_plural" {
+		msg, err = NewPluralMessage(selector, variants)
+	} else {
+		msg, err = NewSelectMessage(selector, variants)
+	}
+	if err != nil {
+		p.errorf(spec, identifier, locale, "%w: %w", ErrYamlDefinitionInvalid, err)
+		return nil
+	}
+
+	return msg
+}
+
+// kindName returns a readable name of a yaml node kind.
+func kindName(kind yaml.Kind) string {
+	switch kind {
+	case yaml.DocumentNode:
+		return "document"
+	case yaml.SequenceNode:
+		return "sequence"
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.ScalarNode:
+		return "scalar"
+	case yaml.AliasNode:
+		return "alias"
+	}
+
+	return "unknown node"
+}
+
+// stripComment removes the # and the following space from every line of a yaml comment.
+func stripComment(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		line = strings.TrimPrefix(line, "#")
+		lines[i] = strings.TrimPrefix(line, " ")
 	}
 
-	return loc, nil
+	return strings.Join(lines, "\n")
 }
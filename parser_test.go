@@ -1,6 +1,8 @@
 package staticmessages_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -58,4 +60,418 @@ HelloUser:
 		require.Equal(t, "de", container.Messages[1].Translations[1].Locale)
 		require.Len(t, container.Messages[1].Translations[1].Message.Vars, 0)
 	})
+
+	t.Run("plurals", func(t *testing.T) {
+		container, err := staticmessages.Parse("plurals", strings.NewReader(`Items:
+  default:
+    _plural: count
+    one: One item
+    other: "%(count)d items"
+  nl:
+    _plural: count
+    =0: Geen items
+    one: Een item
+    other: "%(count)d items"
+`))
+		require.NoError(t, err)
+
+		items := container.Messages[0]
+		require.Equal(t, "count", items.Default.Selector.Name)
+		require.Len(t, items.Default.Variants, 2)
+		require.Equal(t, "nl", items.Translations[0].Locale)
+		require.Equal(t, "=0", items.Translations[0].Message.Variants[0].Key)
+		require.Equal(t, "Geen items", items.Translations[0].Message.Variants[0].Message.Message)
+	})
+
+	t.Run("plural without selector", func(t *testing.T) {
+		_, err := staticmessages.Parse("plurals", strings.NewReader(`Items:
+  default:
+    one: One item
+    other: Items
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+
+	t.Run("plural with invalid category", func(t *testing.T) {
+		_, err := staticmessages.Parse("plurals", strings.NewReader(`Items:
+  default:
+    _plural: count
+    several: Items
+    other: Items
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+
+	t.Run("select", func(t *testing.T) {
+		container, err := staticmessages.Parse("select", strings.NewReader(`Updated:
+  default:
+    _select: gender
+    male: He updated the document
+    female: She updated the document
+    other: They updated the document
+  nl:
+    _select: gender
+    other:
+      _plural: count
+      one: Iemand heeft het document bijgewerkt
+      other: "%(count)d mensen hebben het document bijgewerkt"
+`))
+		require.NoError(t, err)
+
+		updated := container.Messages[0]
+		require.True(t, updated.Default.IsSelect())
+		require.Equal(t, "gender", updated.Default.Selector.Name)
+		require.True(t, updated.Translations[0].Message.Variants[0].Message.IsPlural())
+		require.Len(t, updated.UniqueVars(), 2)
+	})
+
+	t.Run("select and plural", func(t *testing.T) {
+		_, err := staticmessages.Parse("select", strings.NewReader(`Updated:
+  default:
+    _select: gender
+    _plural: count
+    other: They updated the document
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+
+	t.Run("select type mix", func(t *testing.T) {
+		_, err := staticmessages.Parse("select", strings.NewReader(`Updated:
+  default:
+    _select: gender
+    other: They updated the document
+  nl:
+    _plural: gender
+    other: Bijgewerkt
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+
+	t.Run("icu syntax", func(t *testing.T) {
+		container, err := staticmessages.Parse("icu", strings.NewReader(`_syntax: icu
+HelloUser:
+  default: Hello {user}!
+  nl: "Hallo {user}, je hebt {count, plural, one {# bericht} other {# berichten}}"
+Plain:
+  default: "It''s '{literal}'"
+`))
+		require.NoError(t, err)
+
+		require.Len(t, container.Messages, 2)
+		require.Equal(t, "Hello %s!", container.Messages[0].Default.Message)
+		require.True(t, container.Messages[0].Translations[0].Message.IsPlural())
+		require.Equal(t, "It's {literal}", container.Messages[1].Default.Message)
+	})
+
+	t.Run("printf syntax", func(t *testing.T) {
+		container, err := staticmessages.Parse("printf", strings.NewReader(`_syntax: printf
+HelloUser:
+  default: Hello {user}!
+`))
+		require.NoError(t, err)
+		require.Equal(t, "Hello {user}!", container.Messages[0].Default.Message)
+	})
+
+	t.Run("message syntax", func(t *testing.T) {
+		container, err := staticmessages.Parse("syntax", strings.NewReader(`Hint:
+  default: Use {name} here, it's easy
+HelloUser:
+  _syntax: icu
+  default: Hello {user}, it''s {count, number}
+  nl: Hallo {user}
+`))
+		require.NoError(t, err)
+		require.Equal(t, "Use {name} here, it's easy", container.Messages[0].Default.Message)
+		require.Empty(t, container.Messages[0].Syntax)
+		require.Equal(t, "Hello %s, it's %d", container.Messages[1].Default.Message)
+		require.Equal(t, "Hallo %s", container.Messages[1].Translations[0].Message.Message)
+		require.Equal(t, staticmessages.SyntaxICU, container.Messages[1].Syntax)
+
+		_, err = staticmessages.Parse("syntax", strings.NewReader(`HelloUser:
+  _syntax: gettext
+  default: Hello!
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+
+	t.Run("invalid syntax", func(t *testing.T) {
+		_, err := staticmessages.Parse("invalid", strings.NewReader(`_syntax: gettext
+HelloUser:
+  default: Hello!
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+
+	t.Run("package", func(t *testing.T) {
+		container, err := staticmessages.Parse("billing", strings.NewReader(`_package: invoices
+Paid:
+  default: Paid
+`))
+		require.NoError(t, err)
+		require.Equal(t, "invoices", container.Package)
+		require.Len(t, container.Messages, 1)
+	})
+
+	t.Run("invalid package", func(t *testing.T) {
+		_, err := staticmessages.Parse("billing", strings.NewReader(`_package: billing-v2
+Paid:
+  default: Paid
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		container, err := staticmessages.Parse("metadata", strings.NewReader(`# Shown when a user cannot be found.
+NotFound: # Used by the API and the admin.
+  _description: The ID is the database ID.
+  _context: Error page title
+  _example: User 5 not found
+  default: User %(ID)d not found
+  nl: Gebruiker %(ID)d niet gevonden
+Plain:
+  default: Hello
+`))
+		require.NoError(t, err)
+
+		notFound := container.Messages[0]
+		require.Equal(t, "Shown when a user cannot be found.\nUsed by the API and the admin.\nThe ID is the database ID.", notFound.Description)
+		require.Equal(t, "Error page title", notFound.Context)
+		require.Equal(t, "User 5 not found", notFound.Example)
+		require.Len(t, notFound.Translations, 1)
+		require.True(t, notFound.HasDoc())
+
+		require.False(t, container.Messages[1].HasDoc())
+	})
+
+	t.Run("vars", func(t *testing.T) {
+		container, err := staticmessages.Parse("vars", strings.NewReader(`Shipped:
+  _vars:
+    count: uint32
+    order: example.com/shop/orders.ID
+  default:
+    _plural: count
+    one: Order %(order)v shipped with one item
+    other: Order %(order)v shipped with %(count)d items
+  nl: Bestelling %(order)v met %(count)d items verzonden
+`))
+		require.NoError(t, err)
+
+		shipped := container.Messages[0]
+		require.Empty(t, shipped.TypeParams())
+		require.Equal(t, "uint32", shipped.Default.Selector.GoType)
+		require.Equal(t, "uint32", shipped.Translations[0].Message.Var("count").GoType)
+		require.Equal(t, "orders.ID", shipped.Default.Variants[0].Message.Var("order").GoType)
+		require.Equal(t, []string{"github.com/wvell/staticmessages", "example.com/shop/orders"}, container.Imports())
+	})
+
+	t.Run("vars errors", func(t *testing.T) {
+		for _, c := range []struct {
+			yml      string
+			expected error
+		}{
+			{yml: "Foo:\n  _vars:\n    user: int64\n  default: Hello %(ID)d\n", expected: staticmessages.ErrUnknownVar},
+			{yml: "Foo:\n  _vars:\n    ID: string\n  default: Hello %(ID)d\n", expected: staticmessages.ErrGoTypeInvalid},
+			{yml: "Foo:\n  _vars:\n    at: example.com/dates.Day\n  default: Hello at %(at)t\n", expected: staticmessages.ErrGoTypeInvalid},
+			{yml: "Foo:\n  _vars:\n    ID: int32\n  default: Hello %(ID:int64)d\n", expected: staticmessages.ErrVariableTypeMix},
+			{yml: "Foo:\n  _vars: int64\n  default: Hello %(ID)d\n", expected: staticmessages.ErrYamlDefinitionInvalid},
+		} {
+			_, err := staticmessages.Parse("vars", strings.NewReader(c.yml))
+			require.ErrorIs(t, err, c.expected, c.yml)
+		}
+	})
+
+	t.Run("package name collision", func(t *testing.T) {
+		_, err := staticmessages.Parse("vars", strings.NewReader(`Shipped:
+  default: Order %(order:example.com/shop/ids.ID)v shipped
+Paid:
+  default: Order %(order:example.com/billing/ids.ID)v paid
+Failed:
+  default: Order %(order:fmt=example.com/shop/fmt.ID)v failed
+`))
+		require.ErrorIs(t, err, staticmessages.ErrGoTypeInvalid)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 2)
+		require.Equal(t, "Paid", parseErrs[0].Identifier)
+		require.Equal(t, "Failed", parseErrs[1].Identifier)
+
+		_, err = staticmessages.Parse("vars", strings.NewReader(`Shipped:
+  default: Order %(order:example.com/shop/ids.ID)v shipped
+Paid:
+  default: Order %(order:billing=example.com/billing/ids.ID)v paid
+`))
+		require.NoError(t, err)
+	})
+
+	t.Run("reserved identifiers", func(t *testing.T) {
+		_, err := staticmessages.Parse("users", strings.NewReader(`Locales:
+  default: Languages
+Message:
+  Locales:
+    default: Message languages
+NotFound:
+  default: User not found
+NotFoundLocales:
+  default: User languages not found
+`))
+		require.ErrorIs(t, err, staticmessages.ErrReservedIdentifier)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 2)
+		require.Equal(t, "Locales", parseErrs[0].Identifier)
+		require.Equal(t, "MessageLocales", parseErrs[1].Identifier)
+	})
+
+	t.Run("unknown reserved key", func(t *testing.T) {
+		_, err := staticmessages.Parse("metadata", strings.NewReader(`NotFound:
+  _descripton: Typo
+  default: User not found
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+	})
+}
+
+func TestParseError(t *testing.T) {
+	t.Run("position of translation", func(t *testing.T) {
+		_, err := staticmessages.Parse("errors", strings.NewReader(`HelloUser:
+  default: Hello, %(user)s!
+  nl: Hallo, %(user)d!
+`))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+
+		var parseErr *staticmessages.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 3, parseErr.Line)
+		require.Equal(t, 7, parseErr.Column)
+		require.Equal(t, "HelloUser", parseErr.Identifier)
+		require.Equal(t, "nl", parseErr.Locale)
+		require.True(t, strings.HasPrefix(err.Error(), "3:7: HelloUser (nl): yml definition is invalid: "), err.Error())
+	})
+
+	t.Run("position of message", func(t *testing.T) {
+		_, err := staticmessages.Parse("errors", strings.NewReader(`HelloUser:
+  default: Hello, %(user)q!
+`))
+		require.ErrorIs(t, err, staticmessages.ErrUnsupportedFormat)
+
+		var parseErr *staticmessages.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 2, parseErr.Line)
+		require.Equal(t, "", parseErr.Locale)
+	})
+
+	t.Run("yaml syntax error", func(t *testing.T) {
+		_, err := staticmessages.Parse("errors", strings.NewReader("HelloUser:\n  default: Hello\n nl: Hallo\n"))
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+
+		var parseErr *staticmessages.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 2, parseErr.Line)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "errors.yml")
+		err := os.WriteFile(filename, []byte("NotFound:\n  nl: Niet gevonden\n"), 0644)
+		require.NoError(t, err)
+
+		_, err = staticmessages.ParseFile(filename)
+		require.ErrorIs(t, err, staticmessages.ErrYamlDefinitionInvalid)
+		require.Equal(t, filename+":2:3: NotFound: yml definition is invalid: expected default message", err.Error())
+	})
+}
+
+func TestParseFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "errors.yml")
+	err := os.WriteFile(filename, []byte("NotFound:\n  default: Not found\n"), 0644)
+	require.NoError(t, err)
+
+	container, err := staticmessages.ParseFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "Errors", container.Name)
+	require.Len(t, container.Messages, 1)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := staticmessages.Parse("errors", strings.NewReader(`HelloUser:
+  default: Hello, %(user)s!
+  nl: Hallo, %(user)d!
+  de: Hallo, %(user)q!
+Valid:
+  default: Valid
+notCapitalized:
+  default: Hello
+Items:
+  default:
+    _plural: count
+    several: Items
+    other: "%(count)x items"
+NoDefault:
+  nl: Geen standaard
+`))
+	require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+	require.ErrorIs(t, err, staticmessages.ErrUnsupportedFormat)
+	require.ErrorIs(t, err, staticmessages.ErrIdentifierInvalid)
+
+	var parseErrs staticmessages.ParseErrors
+	require.ErrorAs(t, err, &parseErrs)
+	require.Len(t, parseErrs, 5)
+
+	lines := make([]int, 0)
+	for _, parseErr := range parseErrs {
+		lines = append(lines, parseErr.Line)
+	}
+	require.Equal(t, []int{4, 3, 7, 13, 15}, lines)
+	require.Len(t, strings.Split(err.Error(), "\n"), 5)
+}
+
+func TestParseGroups(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		container, err := staticmessages.Parse("errors", strings.NewReader(`User:
+  NotFound:
+    default: User %(ID)d not found
+  Banned:
+    default: User %(ID)d is banned
+    nl: Gebruiker %(ID)d is verbannen
+  Session:
+    Expired:
+      default: Your session expired
+Unknown:
+  default: Unknown error
+`))
+		require.NoError(t, err)
+
+		identifiers := make([]string, 0)
+		for _, msg := range container.Messages {
+			identifiers = append(identifiers, msg.Identifier)
+		}
+		require.Equal(t, []string{"UserNotFound", "UserBanned", "UserSessionExpired", "Unknown"}, identifiers)
+		require.Equal(t, "nl", container.Messages[1].Translations[0].Locale)
+	})
+
+	t.Run("duplicate across groups", func(t *testing.T) {
+		_, err := staticmessages.Parse("errors", strings.NewReader(`User:
+  NotFound:
+    default: User not found
+UserNotFound:
+  default: User not found
+`))
+		require.ErrorIs(t, err, staticmessages.ErrDuplicateIdentifier)
+
+		var parseErr *staticmessages.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 4, parseErr.Line)
+		require.Equal(t, "UserNotFound", parseErr.Identifier)
+	})
+
+	t.Run("group mixed with locales", func(t *testing.T) {
+		_, err := staticmessages.Parse("errors", strings.NewReader(`User:
+  default: User
+  NotFound:
+    default: User not found
+`))
+		require.ErrorIs(t, err, staticmessages.ErrIdentifierInvalid)
+	})
 }
@@ -0,0 +1,325 @@
+package staticmessages
+
+import "strings"
+
+// pluralCategories contains the CLDR plural categories in their canonical order.
+var pluralCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+// pluralRules contains the CLDR plural rules for integers by language.
+// Languages that are not listed use the same rules as english.
+var pluralRules = map[string]func(n int64) string{
+	"am": pluralHindi,
+	"ar": pluralArabic,
+	"be": pluralEastSlavic,
+	"bn": pluralHindi,
+	"bs": pluralSerboCroatian,
+	"ca": pluralItalian,
+	"cs": pluralCzech,
+	"cy": pluralWelsh,
+	"es": pluralItalian,
+	"fa": pluralHindi,
+	"fr": pluralFrench,
+	"ga": pluralIrish,
+	"gu": pluralHindi,
+	"he": pluralHebrew,
+	"hi": pluralHindi,
+	"hr": pluralSerboCroatian,
+	"id": pluralNone,
+	"is": pluralIcelandic,
+	"it": pluralItalian,
+	"ja": pluralNone,
+	"km": pluralNone,
+	"kn": pluralHindi,
+	"ko": pluralNone,
+	"lt": pluralLithuanian,
+	"lv": pluralLatvian,
+	"ms": pluralNone,
+	"my": pluralNone,
+	"pl": pluralPolish,
+	"pt": pluralFrench,
+	"ro": pluralRomanian,
+	"ru": pluralEastSlavic,
+	"sk": pluralCzech,
+	"sl": pluralSlovenian,
+	"sr": pluralSerboCroatian,
+	"th": pluralNone,
+	"uk": pluralEastSlavic,
+	"vi": pluralNone,
+	"zh": pluralNone,
+	"zu": pluralHindi,
+}
+
+// PluralCategory returns the CLDR plural category (zero, one, two, few, many or other) of n for the locale.
+// The locale may contain a region (en-US or en_US), only the language is used to select the rules.
+func PluralCategory(locale string, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		// European portuguese differs from the other portuguese variants.
+		if lang[:i] == "pt" && lang[i+1:] == "pt" {
+			return pluralItalian(n)
+		}
+
+		lang = lang[:i]
+	}
+
+	if rule, ok := pluralRules[lang]; ok {
+		return rule(n)
+	}
+
+	return pluralEnglish(n)
+}
+
+// PluralCategories returns the plural categories integers can have in the locale, in the canonical order.
+func PluralCategories(locale string) []string {
+	found := make([]string, 0, len(pluralCategories))
+	add := func(n int64) {
+		if category := PluralCategory(locale, n); !contains(found, category) {
+			found = append(found, category)
+		}
+	}
+
+	// The rules only depend on the last two digits, except for the many category of the romance languages.
+	for n := int64(0); n < 200; n++ {
+		add(n)
+	}
+	add(1000000)
+
+	categories := make([]string, 0, len(found))
+	for _, category := range pluralCategories {
+		if contains(found, category) {
+			categories = append(categories, category)
+		}
+	}
+
+	return categories
+}
+
+// isPluralKey checks if key is a plural category or an exact match like =0.
+func isPluralKey(key string) bool {
+	if contains(pluralCategories, key) {
+		return true
+	}
+
+	return isExactKey(key)
+}
+
+// isExactKey checks if key is an exact match like =0.
+func isExactKey(key string) bool {
+	if len(key) < 2 || key[0] != '=' {
+		return false
+	}
+
+	for _, r := range key[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func pluralNone(n int64) string {
+	return "other"
+}
+
+func pluralEnglish(n int64) string {
+	if n == 1 {
+		return "one"
+	}
+
+	return "other"
+}
+
+func pluralHindi(n int64) string {
+	if n == 0 || n == 1 {
+		return "one"
+	}
+
+	return "other"
+}
+
+func pluralFrench(n int64) string {
+	switch {
+	case n == 0 || n == 1:
+		return "one"
+	case n%1000000 == 0:
+		return "many"
+	}
+
+	return "other"
+}
+
+func pluralItalian(n int64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n != 0 && n%1000000 == 0:
+		return "many"
+	}
+
+	return "other"
+}
+
+func pluralIcelandic(n int64) string {
+	if n%10 == 1 && n%100 != 11 {
+		return "one"
+	}
+
+	return "other"
+}
+
+func pluralEastSlavic(n int64) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "few"
+	}
+
+	return "many"
+}
+
+func pluralPolish(n int64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "few"
+	}
+
+	return "many"
+}
+
+func pluralCzech(n int64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n >= 2 && n <= 4:
+		return "few"
+	}
+
+	return "other"
+}
+
+func pluralSerboCroatian(n int64) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "few"
+	}
+
+	return "other"
+}
+
+func pluralSlovenian(n int64) string {
+	switch n % 100 {
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	case 3, 4:
+		return "few"
+	}
+
+	return "other"
+}
+
+func pluralLithuanian(n int64) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 19:
+		return "other"
+	case n%10 == 1:
+		return "one"
+	case n%10 >= 2:
+		return "few"
+	}
+
+	return "other"
+}
+
+func pluralLatvian(n int64) string {
+	switch {
+	case n%10 == 0 || (n%100 >= 11 && n%100 <= 19):
+		return "zero"
+	case n%10 == 1:
+		return "one"
+	}
+
+	return "other"
+}
+
+func pluralRomanian(n int64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n == 0 || (n%100 >= 1 && n%100 <= 19):
+		return "few"
+	}
+
+	return "other"
+}
+
+func pluralArabic(n int64) string {
+	switch {
+	case n == 0:
+		return "zero"
+	case n == 1:
+		return "one"
+	case n == 2:
+		return "two"
+	case n%100 >= 3 && n%100 <= 10:
+		return "few"
+	case n%100 >= 11:
+		return "many"
+	}
+
+	return "other"
+}
+
+func pluralHebrew(n int64) string {
+	switch n {
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	}
+
+	return "other"
+}
+
+func pluralIrish(n int64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n == 2:
+		return "two"
+	case n >= 3 && n <= 6:
+		return "few"
+	case n >= 7 && n <= 10:
+		return "many"
+	}
+
+	return "other"
+}
+
+func pluralWelsh(n int64) string {
+	switch n {
+	case 0:
+		return "zero"
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	case 3:
+		return "few"
+	case 6:
+		return "many"
+	}
+
+	return "other"
+}
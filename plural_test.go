@@ -0,0 +1,61 @@
+package staticmessages_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		locale   string
+		n        int64
+		expected string
+	}{
+		{locale: "", n: 0, expected: "other"},
+		{locale: "", n: 1, expected: "one"},
+		{locale: "en", n: 2, expected: "other"},
+		{locale: "nl", n: 1, expected: "one"},
+		{locale: "ja", n: 1, expected: "other"},
+		{locale: "fr", n: 0, expected: "one"},
+		{locale: "fr", n: 1000000, expected: "many"},
+		{locale: "pt-BR", n: 0, expected: "one"},
+		{locale: "pt_PT", n: 0, expected: "other"},
+		{locale: "ru", n: 1, expected: "one"},
+		{locale: "ru", n: 11, expected: "many"},
+		{locale: "ru", n: 22, expected: "few"},
+		{locale: "ru-RU", n: 25, expected: "many"},
+		{locale: "pl", n: 21, expected: "many"},
+		{locale: "pl", n: 23, expected: "few"},
+		{locale: "cs", n: 3, expected: "few"},
+		{locale: "ar", n: 0, expected: "zero"},
+		{locale: "ar", n: 2, expected: "two"},
+		{locale: "ar", n: 105, expected: "few"},
+		{locale: "ar", n: 111, expected: "many"},
+		{locale: "ar", n: 100, expected: "other"},
+		{locale: "cy", n: 6, expected: "many"},
+		{locale: "lv", n: 10, expected: "zero"},
+		{locale: "lt", n: 12, expected: "other"},
+		{locale: "ro", n: 1, expected: "one"},
+		{locale: "ro", n: 101, expected: "few"},
+		{locale: "ro", n: 119, expected: "few"},
+		{locale: "ro", n: 120, expected: "other"},
+		{locale: "en", n: -1, expected: "one"},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s %d", c.locale, c.n), func(t *testing.T) {
+			require.Equal(t, c.expected, staticmessages.PluralCategory(c.locale, c.n))
+		})
+	}
+}
+
+func TestPluralCategories(t *testing.T) {
+	require.Equal(t, []string{"one", "other"}, staticmessages.PluralCategories("en"))
+	require.Equal(t, []string{"other"}, staticmessages.PluralCategories("ja"))
+	require.Equal(t, []string{"one", "few", "many"}, staticmessages.PluralCategories("pl"))
+	require.Equal(t, []string{"one", "many", "other"}, staticmessages.PluralCategories("fr"))
+	require.Equal(t, []string{"zero", "one", "two", "few", "many", "other"}, staticmessages.PluralCategories("cy"))
+}
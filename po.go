@@ -0,0 +1,249 @@
+package staticmessages
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var ErrPOInvalid = errors.New("po file is invalid")
+
+// WritePO writes the messages as a gettext PO file for locale.
+// Every unit is an entry with the unit ID as msgctxt, the default text as msgid and the translation as msgstr.
+// An empty locale writes a POT template without translations.
+func WritePO(msg *Messages, locale string, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	header := []string{"Project-Id-Version: " + msg.Name}
+	if locale != "" {
+		header = append(header, "Language: "+locale)
+	}
+	header = append(header,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+		"X-Generator: msggen",
+	)
+
+	writePOString(bw, "msgid", "")
+	writePOString(bw, "msgstr", strings.Join(header, "\n")+"\n")
+
+	for _, unit := range msg.Units(locale) {
+		bw.WriteString("\n")
+
+		if text := docText(unit.Message); text != "" {
+			for _, line := range strings.Split(text, "\n") {
+				bw.WriteString(strings.TrimSpace("#. "+line) + "\n")
+			}
+		}
+
+		for i, key := range unit.Keys {
+			bw.WriteString("#. " + unit.Selectors[i].Name + ": " + key + "\n")
+		}
+
+		if varRe.MatchString(unit.Source) {
+			bw.WriteString("#, python-format\n")
+		}
+
+		writePOString(bw, "msgctxt", unit.ID)
+		writePOString(bw, "msgid", unit.Source)
+		writePOString(bw, "msgstr", unit.Target)
+	}
+
+	return bw.Flush()
+}
+
+// writePOString writes a keyword with a quoted string, strings with newlines are split over multiple lines.
+func writePOString(w *bufio.Writer, keyword, value string) {
+	if !strings.Contains(strings.TrimSuffix(value, "\n"), "\n") {
+		w.WriteString(keyword + " " + strconv.Quote(value) + "\n")
+		return
+	}
+
+	w.WriteString(keyword + " \"\"\n")
+	for _, line := range strings.SplitAfter(value, "\n") {
+		if line != "" {
+			w.WriteString(strconv.Quote(line) + "\n")
+		}
+	}
+}
+
+// ReadPO reads the units of a PO file written by WritePO, the locale is read from the Language header.
+// Fuzzy entries are returned without a target.
+func ReadPO(r io.Reader) (string, []*Unit, error) {
+	return readPO("", r)
+}
+
+// ReadPOFile reads the units of the PO file at path, see ReadPO.
+func ReadPOFile(path string) (string, []*Unit, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	return readPO(path, f)
+}
+
+// poEntry is an entry of a PO file while it is read.
+type poEntry struct {
+	line  int
+	fuzzy bool
+	// invalid is set when an error is already reported for the entry.
+	invalid bool
+	fields  map[string]*strings.Builder
+}
+
+func readPO(file string, r io.Reader) (string, []*Unit, error) {
+	var locale string
+	var errs ParseErrors
+	units := make([]*Unit, 0)
+
+	errorf := func(line int, format string, args ...any) {
+		errs = append(errs, &ParseError{File: file, Line: line, Err: fmt.Errorf("%w: %s", ErrPOInvalid, fmt.Sprintf(format, args...))})
+	}
+
+	var entry *poEntry
+	var field *strings.Builder
+
+	flush := func() {
+		if entry == nil || len(entry.fields) == 0 {
+			return
+		}
+
+		ctxt, hasCtxt := entry.fields["msgctxt"]
+		msgid, msgstr := entry.fields["msgid"], entry.fields["msgstr"]
+
+		switch {
+		case entry.invalid:
+		case msgid == nil || msgstr == nil:
+			errorf(entry.line, "expected msgid and msgstr")
+		case !hasCtxt && msgid.Len() == 0:
+			// The header contains the locale.
+			for _, line := range strings.Split(msgstr.String(), "\n") {
+				if value, ok := strings.CutPrefix(line, "Language:"); ok {
+					locale = strings.TrimSpace(value)
+				}
+			}
+		case !hasCtxt:
+			errorf(entry.line, "expected msgctxt with the message identifier")
+		default:
+			identifier, keys := splitUnitID(ctxt.String())
+			unit := &Unit{
+				ID:         ctxt.String(),
+				Identifier: identifier,
+				Keys:       keys,
+				Source:     msgid.String(),
+				Target:     msgstr.String(),
+				File:       file,
+				Line:       entry.line,
+			}
+
+			if entry.fuzzy {
+				unit.Target = ""
+			}
+
+			units = append(units, unit)
+		}
+
+		entry, field = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#"):
+			// A comment after the fields of an entry starts a new entry.
+			if entry != nil && len(entry.fields) > 0 {
+				flush()
+			}
+
+			if entry == nil {
+				entry = &poEntry{line: lineNo, fields: make(map[string]*strings.Builder)}
+			}
+
+			if strings.HasPrefix(line, "#,") && strings.Contains(line, "fuzzy") {
+				entry.fuzzy = true
+			}
+
+			continue
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				errorf(lineNo, "string without keyword")
+				continue
+			}
+
+			value, err := strconv.Unquote(line)
+			if err != nil {
+				errorf(lineNo, "invalid string %s", line)
+				continue
+			}
+
+			field.WriteString(value)
+			continue
+		}
+
+		keyword, value, _ := strings.Cut(line, " ")
+		switch keyword {
+		case "msgctxt", "msgid", "msgstr":
+		case "msgid_plural":
+			errorf(lineNo, "plural entries are not supported, plurals are written as an entry per variant")
+			if entry != nil {
+				entry.invalid = true
+			}
+
+			field = nil
+			continue
+		default:
+			if strings.HasPrefix(keyword, "msgstr[") {
+				field = nil
+				continue
+			}
+
+			errorf(lineNo, "unknown keyword %q", keyword)
+			field = nil
+			continue
+		}
+
+		// Entries are not always separated by an empty line.
+		if entry != nil && (entry.fields[keyword] != nil || (keyword == "msgctxt" && entry.fields["msgid"] != nil)) {
+			flush()
+		}
+
+		if entry == nil {
+			entry = &poEntry{line: lineNo, fields: make(map[string]*strings.Builder)}
+		}
+
+		unquoted, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			errorf(lineNo, "invalid string %s", value)
+		}
+
+		field = &strings.Builder{}
+		field.WriteString(unquoted)
+		entry.fields[keyword] = field
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+
+	flush()
+
+	if len(errs) > 0 {
+		return "", nil, errs
+	}
+
+	return locale, units, nil
+}
@@ -0,0 +1,208 @@
+package staticmessages_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+const poSource = `# Shown when a user cannot be found.
+NotFound:
+  _context: Error page title
+  default: User %(ID)d not found
+  nl: Gebruiker %(ID)d niet gevonden
+Files:
+  default:
+    _plural: count
+    =0: No files in %(folder)s
+    one: One file in %(folder)s
+    other: "%(count)d files in %(folder)s"
+Welcome:
+  default: |
+    Dear %(user)s,
+    Welcome!
+`
+
+func TestWritePO(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(poSource))
+	require.NoError(t, err)
+
+	for _, locale := range []string{"", "nl", "pl"} {
+		var buf bytes.Buffer
+		require.NoError(t, staticmessages.WritePO(messages, locale, &buf))
+
+		golden := "po.golden_" + locale
+		if locale == "" {
+			golden = "po.golden_pot"
+		}
+
+		compareGolden(t, buf.Bytes(), golden)
+	}
+}
+
+func TestReadPO(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		messages, err := staticmessages.Parse("users", strings.NewReader(poSource))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, staticmessages.WritePO(messages, "nl", &buf))
+
+		locale, units, err := staticmessages.ReadPO(&buf)
+		require.NoError(t, err)
+		require.Equal(t, "nl", locale)
+
+		expected := messages.Units("nl")
+		require.Len(t, units, len(expected))
+		for i, unit := range units {
+			require.Equal(t, expected[i].ID, unit.ID)
+			require.Equal(t, expected[i].Keys, unit.Keys)
+			require.Equal(t, expected[i].Source, unit.Source)
+			require.Equal(t, expected[i].Target, unit.Target)
+		}
+	})
+
+	t.Run("fuzzy and obsolete entries", func(t *testing.T) {
+		_, units, err := staticmessages.ReadPO(strings.NewReader(`msgid ""
+msgstr "Language: de\n"
+
+#, fuzzy, python-format
+msgctxt "NotFound"
+msgid "User %(ID)d not found"
+msgstr "Benutzer %(ID)d nicht gefunden"
+msgctxt "Files.one"
+msgid "One file"
+msgstr ""
+"Eine "
+"Datei"
+
+#~ msgctxt "Removed"
+#~ msgid "Removed"
+#~ msgstr "Entfernt"
+`))
+		require.NoError(t, err)
+		require.Len(t, units, 2)
+		require.Equal(t, "", units[0].Target)
+		require.Equal(t, "Files", units[1].Identifier)
+		require.Equal(t, []string{"one"}, units[1].Keys)
+		require.Equal(t, "Eine Datei", units[1].Target)
+		require.Equal(t, 8, units[1].Line)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := staticmessages.ReadPO(strings.NewReader(`msgid "Hello"
+msgstr "Hallo"
+
+msgctxt "Files"
+msgid "One file"
+msgid_plural "%(count)d files"
+msgstr[0] "Een bestand"
+`))
+		require.ErrorIs(t, err, staticmessages.ErrPOInvalid)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 2)
+		require.Equal(t, 1, parseErrs[0].Line)
+		require.Equal(t, 6, parseErrs[1].Line)
+	})
+}
+
+func TestImportUnits(t *testing.T) {
+	read := func(t *testing.T, po string) (string, []*staticmessages.Unit) {
+		locale, units, err := staticmessages.ReadPO(strings.NewReader(po))
+		require.NoError(t, err)
+
+		return locale, units
+	}
+
+	t.Run("merge translations", func(t *testing.T) {
+		locale, units := read(t, `msgid ""
+msgstr "Language: nl\n"
+
+msgctxt "NotFound"
+msgid "User %(ID)d not found"
+msgstr "Gebruiker %(ID)d is niet gevonden"
+
+msgctxt "Files.=0"
+msgid "No files in %(folder)s"
+msgstr "Geen bestanden in %(folder)s"
+
+msgctxt "Files.one"
+msgid "One file in %(folder)s"
+msgstr "Een bestand in %(folder)s"
+
+msgctxt "Files.other"
+msgid "%(count)d files in %(folder)s"
+msgstr "%(count)d bestanden in %(folder)s"
+
+msgctxt "Welcome"
+msgid ""
+"Dear %(user)s,\n"
+"Welcome!\n"
+msgstr ""
+"Beste %(user)s,\n"
+"Welkom!\n"
+`)
+
+		updated, err := staticmessages.ImportUnits("users.yml", []byte(poSource), locale, units)
+		require.NoError(t, err)
+		compareGolden(t, updated, "po.golden_import")
+
+		messages, err := staticmessages.Parse("users", bytes.NewReader(updated))
+		require.NoError(t, err)
+
+		files := messages.Messages[1].Translation("nl")
+		require.True(t, files.IsPlural())
+		require.Equal(t, "%d bestanden in %s", files.OtherVariant().Message.Message)
+		require.Equal(t, "Beste %(user)s,\nWelkom!\n", messages.Messages[2].Translation("nl").Raw)
+	})
+
+	t.Run("message syntax", func(t *testing.T) {
+		src := `HelloUser:
+  _syntax: icu
+  default: Hello {user}
+`
+		updated, err := staticmessages.ImportUnits("users.yml", []byte(src), "nl", []*staticmessages.Unit{
+			{ID: "HelloUser", Identifier: "HelloUser", Target: "Hallo {user}"},
+		})
+		require.NoError(t, err)
+
+		messages, err := staticmessages.Parse("users", bytes.NewReader(updated))
+		require.NoError(t, err)
+		require.Equal(t, "Hallo %s", messages.Messages[0].Translation("nl").Message)
+	})
+
+	t.Run("invalid units", func(t *testing.T) {
+		locale, units := read(t, `msgid ""
+msgstr "Language: nl\n"
+
+msgctxt "NotFound"
+msgid "User %(ID)d not found"
+msgstr "Gebruiker %(user)s niet gevonden"
+
+msgctxt "Files.other"
+msgid "%(count)d files in %(folder)s"
+msgstr "%(count)s bestanden"
+
+msgctxt "Removed"
+msgid "Removed"
+msgstr "Verwijderd"
+`)
+
+		_, err := staticmessages.ImportUnits("users.yml", []byte(poSource), locale, units)
+		require.ErrorIs(t, err, staticmessages.ErrUnknownVar)
+		require.ErrorIs(t, err, staticmessages.ErrVariableTypeMix)
+		require.ErrorIs(t, err, staticmessages.ErrUnknownUnit)
+
+		var parseErrs staticmessages.ParseErrors
+		require.ErrorAs(t, err, &parseErrs)
+		require.Len(t, parseErrs, 3)
+		require.Equal(t, "NotFound", parseErrs[0].Identifier)
+		require.Equal(t, "nl", parseErrs[0].Locale)
+		require.Equal(t, 4, parseErrs[0].Line)
+	})
+}
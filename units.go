@@ -0,0 +1,442 @@
+package staticmessages
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// Unit is a translatable text of a message, translation files like PO files contain a unit per entry.
+// A message written as a mapping of plural or select variants has a unit for every variant, other messages are a single unit.
+type Unit struct {
+	// ID is the identifier followed by the variant keys, like Files.one.
+	ID         string
+	Identifier string
+	// Keys are the variant keys from the outer to the inner variant, Selectors contains the selector of every key.
+	Keys      []string
+	Selectors []*Var
+	// Source is the text of the default message.
+	Source string
+	// Target is the translated text, it is empty when the unit is not translated.
+	Target string
+	// Message is the message of the unit, it is nil for units read from a translation file.
+	Message *LocalizedMessage
+	// File and Line are the position of units read from a translation file.
+	File string
+	Line int
+}
+
+// Units returns the units of all messages with the translations of locale as target.
+// Plural variants contain the categories of locale, an empty locale returns the variants of the default messages.
+func (c Messages) Units(locale string) []*Unit {
+	units := make([]*Unit, 0)
+	for _, l := range c.Messages {
+		var target *Message
+		if locale != "" {
+			target = l.Translation(locale)
+		}
+
+		units = append(units, l.units(locale, l.Default, target, nil, nil)...)
+	}
+
+	return units
+}
+
+// units returns the units of source, keys and selectors are the variants that lead to source.
+func (l *LocalizedMessage) units(locale string, source, target *Message, keys []string, selectors []*Var) []*Unit {
+	if source.Raw != "" || len(source.Variants) == 0 {
+		unit := &Unit{
+			ID:         unitID(l.Identifier, keys),
+			Identifier: l.Identifier,
+			Keys:       keys,
+			Selectors:  selectors,
+			Source:     source.Raw,
+			Message:    l,
+		}
+
+		if target != nil {
+			unit.Target = target.Raw
+		}
+
+		return []*Unit{unit}
+	}
+
+	// A translation without variants is the target of every variant.
+	structured := target != nil && target.Raw == "" && len(target.Variants) > 0
+
+	units := make([]*Unit, 0)
+	for _, key := range variantKeys(locale, source, target, structured) {
+		variant := source.variant(key)
+		if variant == nil {
+			variant = source.OtherVariant()
+		}
+
+		variantTarget := target
+		if structured {
+			variantTarget = nil
+			if v := target.variant(key); v != nil {
+				variantTarget = v.Message
+			}
+		}
+
+		units = append(units, l.units(
+			locale,
+			variant.Message,
+			variantTarget,
+			append(keys[:len(keys):len(keys)], key),
+			append(selectors[:len(selectors):len(selectors)], source.Selector),
+		)...)
+	}
+
+	return units
+}
+
+// variantKeys returns the keys of the variants of source in the translation of locale.
+func variantKeys(locale string, source, target *Message, structured bool) []string {
+	keys := make([]string, 0)
+	add := func(key string) {
+		if !contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, v := range source.Variants {
+		if !source.IsPlural() || v.Exact() != "" || locale == "" {
+			add(v.Key)
+		}
+	}
+
+	if structured {
+		for _, v := range target.Variants {
+			add(v.Key)
+		}
+	}
+
+	if !source.IsPlural() {
+		return keys
+	}
+
+	if locale != "" {
+		for _, category := range PluralCategories(locale) {
+			add(category)
+		}
+	}
+
+	// The other variant is required, even in languages that don't use it for integers.
+	add("other")
+
+	// Exact matches first, followed by the categories in the canonical order.
+	sorted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if isExactKey(key) {
+			sorted = append(sorted, key)
+		}
+	}
+
+	for _, category := range pluralCategories {
+		if contains(keys, category) {
+			sorted = append(sorted, category)
+		}
+	}
+
+	return sorted
+}
+
+// variant returns the variant with key, nil is returned if m has no such variant.
+func (m *Message) variant(key string) *Variant {
+	for _, v := range m.Variants {
+		if v.Key == key {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func unitID(identifier string, keys []string) string {
+	return strings.Join(append([]string{identifier}, keys...), ".")
+}
+
+// splitUnitID splits a unit ID into the identifier and the variant keys.
+func splitUnitID(id string) (string, []string) {
+	identifier, keys, ok := strings.Cut(id, ".")
+	if !ok {
+		return identifier, nil
+	}
+
+	return identifier, strings.Split(keys, ".")
+}
+
+// ImportUnits sets the targets of units as the translations of locale in the yml document src and returns the updated document.
+// An empty locale sets the default messages. Units without a target are skipped. The document is not changed when a unit
+// is unknown or uses placeholders that don't match the vars of the default message, all errors are returned as ParseErrors.
+func ImportUnits(file string, src []byte, locale string, units []*Unit) ([]byte, error) {
+	base := filepath.Base(file)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+
+	messages, err := parse(file, name, bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, err
+	}
+
+	root := doc.Content[0]
+
+	// The messages contain the syntax of the file and of every message.
+	changed, err := importTargets(messages, locale, "", units)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make(map[string]*specRef)
+	specNodes(&specRef{node: root, owned: true}, "", specs)
+
+	for _, l := range messages.Messages {
+		if messageUnits, ok := changed[l.Identifier]; ok {
+			key := locale
+			if locale == "" {
+				key = "default"
+			}
+
+			setMappingValue(specs[l.Identifier].editable(), key, unitsNode(messageUnits, 0))
+		}
+	}
+
+	clearMergeTags(&doc)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+
+	// Check the result, a plural without an other variant for example is only detected when the message is parsed.
+	if _, err := parse(file, name, bytes.NewReader(buf.Bytes())); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// importTargets sets the targets of units as the translations of locale and returns the units of every changed message by identifier.
+// The targets are parsed with syntax, or with the syntax of their message when syntax is empty, and checked against the vars
+// of the default messages.
+func importTargets(messages *Messages, locale string, syntax Syntax, units []*Unit) (map[string][]*Unit, error) {
+	expected := make(map[string]*Unit)
+	for _, unit := range messages.Units(locale) {
+		// The target of the default messages is the source.
+		if locale == "" {
+			unit.Target = unit.Source
+		}
+
+		expected[unit.ID] = unit
+	}
+
+	changed := make(map[string]bool)
+	var errs ParseErrors
+
+	for _, unit := range units {
+		if unit.Target == "" {
+			continue
+		}
+
+		exp, ok := expected[unit.ID]
+		if !ok {
+			errs = append(errs, unitError(unit, locale, fmt.Errorf("%w: %q", ErrUnknownUnit, unit.ID)))
+			continue
+		}
+
+		targetSyntax := syntax
+		if targetSyntax == "" {
+			targetSyntax = exp.Message.Syntax
+		}
+
+		if err := checkUnitTarget(exp.Message, unit.Target, targetSyntax); err != nil {
+			errs = append(errs, unitError(unit, locale, err))
+			continue
+		}
+
+		if exp.Target != unit.Target {
+			exp.Target = unit.Target
+			changed[exp.Identifier] = true
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	messageUnits := make(map[string][]*Unit)
+	for _, unit := range messages.Units(locale) {
+		if changed[unit.Identifier] {
+			messageUnits[unit.Identifier] = append(messageUnits[unit.Identifier], expected[unit.ID])
+		}
+	}
+
+	return messageUnits, nil
+}
+
+// checkUnitTarget checks if target only uses the vars of the default message of l.
+func checkUnitTarget(l *LocalizedMessage, target string, syntax Syntax) error {
+	msg, err := parseMessageSyntax(target, syntax)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range msg.UniqueVars() {
+		if l.Default.Var(v.Name) == nil {
+			return fmt.Errorf("%w: %q is not used by the default message", ErrUnknownVar, v.Name)
+		}
+	}
+
+	return varTypesConsistent(l.Default, msg)
+}
+
+func unitError(unit *Unit, locale string, err error) *ParseError {
+	identifier, _ := splitUnitID(unit.ID)
+
+	return &ParseError{
+		File:       unit.File,
+		Line:       unit.Line,
+		Identifier: identifier,
+		Locale:     locale,
+		Err:        err,
+	}
+}
+
+// unitsNode converts the units of a message into the yml value of a translation, depth is the number of keys that are handled.
+// Nil is returned when none of the units has a target.
+func unitsNode(units []*Unit, depth int) *yaml.Node {
+	if len(units) == 1 && len(units[0].Keys) == depth {
+		if units[0].Target == "" {
+			return nil
+		}
+
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: units[0].Target}
+		if strings.Contains(units[0].Target, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+
+		return node
+	}
+
+	selector := units[0].Selectors[depth]
+	kind := "_select"
+	if selector.Type == VarTypeInt {
+		kind = "_plural"
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(node, kind, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: selector.Name})
+
+	keys := make([]string, 0)
+	groups := make(map[string][]*Unit)
+	for _, unit := range units {
+		key := unit.Keys[depth]
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], unit)
+	}
+
+	for _, key := range keys {
+		if value := unitsNode(groups[key], depth+1); value != nil {
+			setMappingValue(node, key, value)
+		}
+	}
+
+	if len(node.Content) == 2 {
+		return nil
+	}
+
+	return node
+}
+
+// setMappingValue sets the value of key in a mapping node, the key is added at the end if it doesn't exist.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	if value == nil {
+		return
+	}
+
+	for i := 0; (i + 1) < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// specRef is the spec of a message or group in a yml document.
+type specRef struct {
+	// parent is the group that contains the spec, it is nil for the top level of the document.
+	parent *specRef
+	key    string
+	// node is the spec, it can be shared with other specs through an alias or merge key.
+	node *yaml.Node
+	// owned is set when node is part of parent and can be changed without changing other specs.
+	owned bool
+}
+
+// editable returns the spec node that can be changed without changing other specs.
+// Specs that are an alias or part of a merge key are copied into the parent first.
+func (r *specRef) editable() *yaml.Node {
+	if r.owned {
+		return r.node
+	}
+
+	group := r.parent.editable()
+
+	// The copy uses a merge key when the spec has an anchor to keep the shared values in one place.
+	spec := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: append([]*yaml.Node(nil), r.node.Content...)}
+	if r.node.Anchor != "" {
+		spec.Content = []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!merge", Value: "<<"},
+			{Kind: yaml.AliasNode, Value: r.node.Anchor, Alias: r.node},
+		}
+	}
+
+	// A copied parent contains a merge key that includes the spec, the copy is added as an explicit key.
+	setMappingValue(group, r.key, spec)
+	r.node, r.owned = spec, true
+
+	return spec
+}
+
+// specNodes collects the spec of every message by identifier, groups are handled like parseGroup does.
+func specNodes(parent *specRef, prefix string, specs map[string]*specRef) {
+	for _, pair := range mappingPairs(parent.node) {
+		spec := deref(pair.value)
+
+		if spec.Kind != yaml.MappingNode {
+			continue
+		}
+
+		ref := &specRef{
+			parent: parent,
+			key:    pair.key.Value,
+			node:   spec,
+			owned:  parent.owned && !pair.merged && pair.value.Kind != yaml.AliasNode,
+		}
+
+		if isGroup(resolve(spec)) {
+			specNodes(ref, prefix+pair.key.Value, specs)
+			continue
+		}
+
+		specs[prefix+pair.key.Value] = ref
+	}
+}
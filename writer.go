@@ -1,37 +1,580 @@
 package staticmessages
 
 import (
+	"bytes"
 	_ "embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
 	"io"
+	"slices"
+	"strconv"
+	"strings"
 	"text/template"
 )
 
+var ErrGeneratedCodeInvalid = errors.New("generated code is invalid")
+
 var (
 	//go:embed messages.gotmpl
 	rawMessageTpl string
+	//go:embed package.gotmpl
+	rawPackageTpl string
 
 	messageTpl *template.Template
+	packageTpl *template.Template
 
 	funcMap = template.FuncMap{
-		"sub": func(a, b int) int {
-			return a - b
+		"doc":        doc,
+		"quote":      strconv.Quote,
+		"typeParams": typeParams,
+		"params":     params,
+		"field":      field,
+		"fieldType":  fieldType,
+		"fieldValue": fieldValue,
+		"errorVars":  errorVars,
+		"varLiteral": varLiteral,
+		"strings":    stringsLiteral,
+		"translated": translatedLocales,
+		"sorted":     sortedLocales,
+		"arg":        arg,
+		"unexported": unexported,
+		// mark records the message and locale of the code that follows, see writeSource.
+		"mark": func(identifier, locale string) string { return "" },
+		"generated": func(container string, l *LocalizedMessage) generated {
+			return generated{Name: container + l.Identifier, LocalizedMessage: l}
 		},
-		"add": func(a, b int) int {
-			return a + b
+		"branch": func(locale string, msg *Message, indent string) branch {
+			return branch{
+				Locale:  locale,
+				Message: msg,
+				Indent:  indent,
+			}
 		},
 	}
 )
 
+// branch is the input of the return template, it renders msg for locale.
+// Nested lines are prefixed with Indent.
+type branch struct {
+	Locale  string
+	Message *Message
+	Indent  string
+}
+
+// generated is a message with the name of its generated function.
+type generated struct {
+	Name string
+	*LocalizedMessage
+}
+
+// paramTypes contains the Go parameter type of every var type.
+// Ints and floats use the type parameters of the generated function.
+var paramTypes = map[VarType]string{
+	VarTypeString:   "string",
+	VarTypeInt:      "I",
+	VarTypeFloat:    "F",
+	VarTypeBool:     "bool",
+	VarTypeTime:     "time.Time",
+	VarTypeDuration: "time.Duration",
+	VarTypeAny:      "any",
+	VarTypeError:    "error",
+}
+
+// paramType returns the Go type of a var in the generated function signature.
+func paramType(v *Var) string {
+	if v.GoType != "" {
+		return v.GoType
+	}
+
+	return paramTypes[v.Type]
+}
+
+// constraints contains the constraint of the type parameters, the constraints are declared by WritePackage.
+var constraints = map[VarType]string{
+	VarTypeInt:   "Integer",
+	VarTypeFloat: "Float",
+}
+
+// typeParams returns the type parameter list of the generated function of l, it is empty when l has no untyped ints or floats.
+func typeParams(l *LocalizedMessage) string {
+	params := make([]string, 0, 2)
+	for _, typ := range l.TypeParams() {
+		params = append(params, paramTypes[typ]+" "+constraints[typ])
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// params returns the parameter list of vars.
+func params(vars []*Var) string {
+	params := make([]string, 0, len(vars))
+	for _, v := range vars {
+		params = append(params, v.Name+" "+paramType(v))
+	}
+
+	return strings.Join(params, ", ")
+}
+
+// unexported returns name with a lowercase first letter.
+func unexported(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// field returns the name of the struct field that holds v.
+func field(v *Var) string {
+	return strings.ToUpper(v.Name[:1]) + v.Name[1:]
+}
+
+// fieldTypes contains the struct field type of the var types that are passed as type parameters.
+var fieldTypes = map[VarType]string{
+	VarTypeInt:   "int64",
+	VarTypeFloat: "float64",
+}
+
+// fieldType returns the Go type of the struct field that holds v, untyped ints and floats are stored as int64 and float64.
+func fieldType(v *Var) string {
+	if tp, ok := fieldTypes[v.Type]; ok && v.GoType == "" {
+		return tp
+	}
+
+	return paramType(v)
+}
+
+// fieldValue returns the Go expression that converts the parameter v to the type of its struct field.
+func fieldValue(v *Var) string {
+	if tp, ok := fieldTypes[v.Type]; ok && v.GoType == "" {
+		return tp + "(" + v.Name + ")"
+	}
+
+	return v.Name
+}
+
+// errorVars returns the vars of type error, the generated error types wrap them.
+func errorVars(vars []*Var) []*Var {
+	errs := make([]*Var, 0)
+	for _, v := range vars {
+		if v.Type == VarTypeError && v.GoType == "" {
+			errs = append(errs, v)
+		}
+	}
+
+	return errs
+}
+
+// varTypeNames contains the name of the VarType var of every var type.
+var varTypeNames = map[VarType]string{
+	VarTypeString:   "VarTypeString",
+	VarTypeInt:      "VarTypeInt",
+	VarTypeFloat:    "VarTypeFloat",
+	VarTypeBool:     "VarTypeBool",
+	VarTypeTime:     "VarTypeTime",
+	VarTypeDuration: "VarTypeDuration",
+	VarTypeAny:      "VarTypeAny",
+	VarTypeError:    "VarTypeError",
+}
+
+// varLiteral returns v as a staticmessages.Var composite literal without the type.
+func varLiteral(v *Var) string {
+	literal := "{Name: " + strconv.Quote(v.Name) + ", Type: staticmessages." + varTypeNames[v.Type]
+	if v.GoType != "" {
+		literal += ", GoType: " + strconv.Quote(v.GoType)
+	}
+
+	return literal + "}"
+}
+
+// stringsLiteral returns values as a []string composite literal.
+func stringsLiteral(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// translatedLocales returns the locales of the translations of l in alphabetical order.
+func translatedLocales(l *LocalizedMessage) []string {
+	locales := make([]string, 0, len(l.Translations))
+	for _, tr := range l.Translations {
+		locales = append(locales, tr.Locale)
+	}
+
+	return sortedLocales(locales)
+}
+
+// sortedLocales returns a sorted copy of locales.
+func sortedLocales(locales []string) []string {
+	sorted := slices.Clone(locales)
+	slices.Sort(sorted)
+
+	return sorted
+}
+
+// packageLocales returns the locales of the translations of all messages in alphabetical order.
+func packageLocales(messages []*Messages) []string {
+	locales := make([]string, 0)
+	for _, msg := range messages {
+		for _, locale := range msg.Locales() {
+			if !contains(locales, locale) {
+				locales = append(locales, locale)
+			}
+		}
+	}
+
+	return sortedLocales(locales)
+}
+
+// arg returns the Go expression that passes v to fmt.Sprintf.
+func arg(v *Var) string {
+	if v.Type == VarTypeTime {
+		return v.Name + ".Format(" + strconv.Quote(v.Layout) + ")"
+	}
+
+	return v.Name
+}
+
+// doc renders the documentation of a message as a Go comment.
+func doc(l *LocalizedMessage) string {
+	lines := strings.Split(docText(l), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace("// " + line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// docText returns the description, context and example of a message as paragraphs.
+func docText(l *LocalizedMessage) string {
+	paragraphs := make([]string, 0, 3)
+	if l.Description != "" {
+		paragraphs = append(paragraphs, l.Description)
+	}
+
+	if l.Context != "" {
+		paragraphs = append(paragraphs, "Context: "+l.Context)
+	}
+
+	if l.Example != "" {
+		paragraphs = append(paragraphs, "Example: "+l.Example)
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
 func init() {
 	messageTpl = template.Must(template.New("messages").Funcs(funcMap).Parse(rawMessageTpl))
+	packageTpl = template.Must(template.New("package").Funcs(funcMap).Parse(rawPackageTpl))
+}
+
+// WriteOption changes the code that Write generates.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	errors       bool
+	localizables bool
+	catalog      bool
 }
 
-func Write(msg *Messages, pkg string, w io.Writer) error {
-	return messageTpl.Execute(w, map[string]any{
-		"Package":       pkg,
-		"Messages":      msg,
-		"VarTypeInt":    VarTypeInt,
-		"VarTypeString": VarTypeString,
-		"VarTypeFloat":  VarTypeFloat,
+// WithErrors generates an error type for every message. NewXxxError returns an error that holds the vars, Error returns
+// the default message and the error is a staticmessages.Localizable. The errors match the ErrXxx sentinel with errors.Is
+// and wrap the error vars of the message.
+func WithErrors() WriteOption {
+	return func(o *writeOptions) {
+		o.errors = true
+	}
+}
+
+// WithLocalizables generates a XxxMsg function for every message that returns the message as a staticmessages.Localizable.
+// The Localizable holds the vars and renders the message when Localize or LocalizeIn is called.
+func WithLocalizables() WriteOption {
+	return func(o *writeOptions) {
+		o.localizables = true
+	}
+}
+
+// WithCatalog registers the messages in the Catalog var of the package, the var is declared by WritePackage.
+// The messages are registered by the name of their function.
+func WithCatalog() WriteOption {
+	return func(o *writeOptions) {
+		o.catalog = true
+	}
+}
+
+// Write writes the Go code of msg, the code is formatted with gofmt. The package needs the code of WritePackage as well.
+// The names in the code are checked before it is generated, nothing is written when the code would be invalid. Code that
+// can't be formatted is returned as a ParseError with the identifier and locale of the message that produced it.
+func Write(msg *Messages, pkg string, w io.Writer, opts ...WriteOption) error {
+	o := newWriteOptions(opts)
+
+	std := []string{strconv.Quote("context"), strconv.Quote("fmt")}
+	if o.errors {
+		std = append(std, strconv.Quote("errors"))
+	}
+
+	names := msg.importNames()
+	imports := make([]string, 0)
+	for _, path := range msg.imports(o.errors || o.localizables || o.catalog) {
+		spec := strconv.Quote(path)
+		if name, ok := names[path]; ok && name != path[strings.LastIndex(path, "/")+1:] {
+			spec = name + " " + spec
+		}
+
+		// The packages of the standard library don't have a dot in the first element of their path.
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			imports = append(imports, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+
+	if err := checkNames(msg, o); err != nil {
+		return err
+	}
+
+	if err := checkDeclarations(declarations(msg, o)); err != nil {
+		return err
+	}
+
+	return writeSource(messageTpl, map[string]any{
+		"Package":      pkg,
+		"Messages":     msg,
+		"StdImports":   std,
+		"Imports":      imports,
+		"Errors":       o.errors,
+		"Localizables": o.localizables,
+		"Catalog":      o.catalog,
+	}, w)
+}
+
+// WritePackage writes the code that the files of a package share, it must be written once per package with the messages of
+// every file in the package. It declares the Integer and Float constraints of the generated functions.
+func WritePackage(messages []*Messages, pkg string, w io.Writer, opts ...WriteOption) error {
+	o := newWriteOptions(opts)
+
+	// The files of the package share the declarations of the package.
+	decls := make([]declaration, 0)
+	for _, msg := range messages {
+		decls = append(decls, declarations(msg, o)...)
+	}
+
+	if err := checkDeclarations(decls); err != nil {
+		return err
+	}
+
+	var typeParams bool
+	for _, msg := range messages {
+		for _, l := range msg.Messages {
+			typeParams = typeParams || len(l.TypeParams()) > 0
+		}
+	}
+
+	return writeSource(packageTpl, map[string]any{
+		"Package":     pkg,
+		"Locales":     packageLocales(messages),
+		"Catalog":     o.catalog,
+		"Constraints": typeParams,
+	}, w)
+}
+
+// declaration is a name that the generated code declares in the package, identifier is the message of messages that
+// declares it and is empty for the declarations of the file.
+type declaration struct {
+	name       string
+	messages   *Messages
+	identifier string
+}
+
+// declarations returns the package level declarations of the code that Write generates for msg.
+func declarations(msg *Messages, o writeOptions) []declaration {
+	decls := []declaration{
+		{name: msg.Name + "Locales", messages: msg},
+		{name: msg.Name + "MessageLocales", messages: msg},
+		{name: unexported(msg.Name) + "MessageLocales", messages: msg},
+	}
+
+	for _, l := range msg.Messages {
+		name := msg.Name + l.Identifier
+		decls = append(decls, declaration{name: name, messages: msg, identifier: l.Identifier})
+
+		if o.localizables {
+			decls = append(decls, declaration{name: name + "Msg", messages: msg, identifier: l.Identifier})
+		}
+
+		if o.errors {
+			decls = append(decls,
+				declaration{name: "Err" + name, messages: msg, identifier: l.Identifier},
+				declaration{name: name + "Error", messages: msg, identifier: l.Identifier},
+				declaration{name: "New" + name + "Error", messages: msg, identifier: l.Identifier},
+			)
+		}
+	}
+
+	return decls
+}
+
+// checkDeclarations returns an error when decls contain a name twice, like the error type of Save and the function of SaveError.
+func checkDeclarations(decls []declaration) error {
+	declared := make(map[string]declaration)
+	for _, decl := range decls {
+		if other, ok := declared[decl.name]; ok {
+			return fmt.Errorf("%w: %s is declared for %s and %s", ErrGeneratedCodeInvalid, decl.name, other, decl)
+		}
+
+		declared[decl.name] = decl
+	}
+
+	return nil
+}
+
+// String describes where d comes from in an error.
+func (d declaration) String() string {
+	if d.identifier == "" {
+		return "the messages of " + d.messages.Name
+	}
+
+	return "message " + d.identifier + " of " + d.messages.Name
+}
+
+// checkNames returns an error when an identifier or var of msg can't be used in the generated code.
+// The parser doesn't create invalid names, messages that are built in code can contain them. Vars can also be named like
+// a type parameter or a name that the code of an option uses, those are only reserved when the code uses them.
+func checkNames(msg *Messages, o writeOptions) error {
+	for _, l := range msg.Messages {
+		if !identifierRe.MatchString(l.Identifier) {
+			return fmt.Errorf("%w: message %q: %w", ErrGeneratedCodeInvalid, l.Identifier, ErrIdentifierInvalid)
+		}
+
+		reserved := reservedVarNames(l, o)
+
+		if err := checkVarNames(l.Default, reserved); err != nil {
+			return fmt.Errorf("%w: message %s: %w", ErrGeneratedCodeInvalid, l.Identifier, err)
+		}
+
+		for _, tr := range l.Translations {
+			if err := checkVarNames(tr.Message, reserved); err != nil {
+				return fmt.Errorf("%w: message %s, locale %s: %w", ErrGeneratedCodeInvalid, l.Identifier, tr.Locale, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// errorMethods contains the methods of the generated error types, the vars are fields with a capitalized name.
+var errorMethods = []string{"Error", "Localize", "LocalizeIn", "Is", "Unwrap"}
+
+// reservedVarNames returns the var names that the code of l uses, like the type parameters of the function and the names
+// that the code of the options uses.
+func reservedVarNames(l *LocalizedMessage, o writeOptions) []string {
+	reserved := make([]string, 0)
+	for _, typ := range l.TypeParams() {
+		reserved = append(reserved, paramTypes[typ])
+	}
+
+	if o.localizables {
+		// The Msg functions pass the vars as []any to a func(context.Context) closure.
+		reserved = append(reserved, "any", "context")
+	}
+
+	if o.errors {
+		for _, method := range errorMethods {
+			reserved = append(reserved, method, unexported(method))
+		}
+	}
+
+	return reserved
+}
+
+// checkVarNames returns an error when a var of m is not a valid parameter name or is one of the reserved names.
+func checkVarNames(m *Message, reserved []string) error {
+	for _, v := range m.allVars() {
+		if !varNameRe.MatchString(v.Name) {
+			return fmt.Errorf("var %q must contain only letters", v.Name)
+		}
+
+		if isReservedKeyword(v.Name) || contains(reserved, v.Name) {
+			return fmt.Errorf("var %q: %w", v.Name, ErrReservedKeyword)
+		}
+	}
+
+	return nil
+}
+
+func newWriteOptions(opts []WriteOption) writeOptions {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// sourceMark is the message and locale of the generated code from line on, the identifier is empty for the code of the file.
+type sourceMark struct {
+	line       int
+	identifier string
+	locale     string
+}
+
+// writeSource executes tpl and writes the formatted code to w, nothing is written when the code can't be formatted.
+// The error of invalid code is a ParseError with the message and locale that produced the invalid line.
+func writeSource(tpl *template.Template, data map[string]any, w io.Writer) error {
+	tpl, err := tpl.Clone()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	marks := make([]sourceMark, 0)
+
+	// The template writes to buf while it is executed, the text before a mark is in buf when mark is called.
+	tpl.Funcs(template.FuncMap{
+		"mark": func(identifier, locale string) string {
+			marks = append(marks, sourceMark{line: bytes.Count(buf.Bytes(), []byte("\n")) + 1, identifier: identifier, locale: locale})
+			return ""
+		},
 	})
+
+	if err := tpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return sourceError(buf.Bytes(), marks, err)
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+// sourceError returns the format error of the generated src as a ParseError of the message and locale of the invalid line.
+func sourceError(src []byte, marks []sourceMark, err error) error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return fmt.Errorf("%w: %w", ErrGeneratedCodeInvalid, err)
+	}
+
+	lines := strings.Split(string(src), "\n")
+	line := min(list[0].Pos.Line, len(lines))
+	parseErr := &ParseError{Err: fmt.Errorf("%w: %s: %s", ErrGeneratedCodeInvalid, list[0].Msg, strings.TrimSpace(lines[line-1]))}
+
+	// The last mark before the line is the code that contains it.
+	for _, mark := range marks {
+		if mark.line > line {
+			break
+		}
+
+		parseErr.Identifier, parseErr.Locale = mark.identifier, mark.locale
+	}
+
+	return parseErr
 }
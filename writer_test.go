@@ -5,6 +5,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -62,19 +63,372 @@ func TestWriteTemplateWithoutLocales(t *testing.T) {
 	writeMessages(t, message, "template.golden_no_locales")
 }
 
+func TestWriteTemplateWithPlurals(t *testing.T) {
+	defaultMsg, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+		newVariant(t, "one", "One file in %(folder)s"),
+		newVariant(t, "other", "%(count)d files in %(folder)s"),
+	})
+	require.NoError(t, err)
+
+	localized, err := staticmessages.NewLocalizedMessage("Files", defaultMsg)
+	require.NoError(t, err)
+
+	nlMsg, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+		newVariant(t, "=0", "Geen bestanden in %(folder)s"),
+		newVariant(t, "one", "Een bestand in %(folder)s"),
+		newVariant(t, "other", "%(count)d bestanden in %(folder)s"),
+	})
+	require.NoError(t, err)
+
+	err = localized.AddTranslation("nl", nlMsg)
+	require.NoError(t, err)
+
+	message := &staticmessages.Messages{
+		Name: "Test",
+		Messages: []*staticmessages.LocalizedMessage{
+			localized,
+		},
+	}
+
+	writeMessages(t, message, "template.golden_plurals")
+}
+
+func TestWriteTemplateWithSelect(t *testing.T) {
+	defaultMsg, err := staticmessages.NewSelectMessage("gender", []*staticmessages.Variant{
+		newVariant(t, "male", "He updated %(document)s"),
+		newVariant(t, "female", "She updated %(document)s"),
+		newVariant(t, "other", "They updated %(document)s"),
+	})
+	require.NoError(t, err)
+
+	localized, err := staticmessages.NewLocalizedMessage("Updated", defaultMsg)
+	require.NoError(t, err)
+
+	plural, err := staticmessages.NewPluralMessage("count", []*staticmessages.Variant{
+		newVariant(t, "one", "Iemand heeft %(document)s bijgewerkt"),
+		newVariant(t, "other", "%(count)d mensen hebben %(document)s bijgewerkt"),
+	})
+	require.NoError(t, err)
+
+	nlMsg, err := staticmessages.NewSelectMessage("gender", []*staticmessages.Variant{
+		newVariant(t, "male", "Hij heeft %(document)s bijgewerkt"),
+		{Key: "other", Message: plural},
+	})
+	require.NoError(t, err)
+
+	err = localized.AddTranslation("nl", nlMsg)
+	require.NoError(t, err)
+
+	message := &staticmessages.Messages{
+		Name: "Test",
+		Messages: []*staticmessages.LocalizedMessage{
+			localized,
+		},
+	}
+
+	writeMessages(t, message, "template.golden_select")
+}
+
+func TestWriteTemplateWithDoc(t *testing.T) {
+	defaultMsg, err := staticmessages.ParseMessage("User %(ID)d not found")
+	require.NoError(t, err)
+
+	localized, err := staticmessages.NewLocalizedMessage("NotFound", defaultMsg)
+	require.NoError(t, err)
+
+	localized.Description = "Shown when a user cannot be found.\nThe ID is the database ID."
+	localized.Context = "Error page title"
+	localized.Example = "User 5 not found"
+
+	message := &staticmessages.Messages{
+		Name: "Test",
+		Messages: []*staticmessages.LocalizedMessage{
+			localized,
+		},
+	}
+
+	writeMessages(t, message, "template.golden_doc")
+}
+
+func TestWriteTemplateWithEscaping(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`Quoted:
+  default: Say "hello" to C:\Users\%(user)s
+  nl: 'Zeg "hallo" tegen 100% van de gebruikers'
+Multiline:
+  default: |
+    Dear %(user)s,
+
+    Your discount is 10%.
+  nl: >-
+    Beste %(user)s,
+    je korting is 10%%.
+`))
+	require.NoError(t, err)
+
+	writeMessages(t, message, "template.golden_escaping")
+}
+
+func TestWriteTemplateWithVarTypes(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`Shipped:
+  default: Order %(id)v shipped at %(at)t after %(took)D, gift %(gift)b
+  nl: Bestelling %(id)v verzonden op %(at)t
+Delivered:
+  _syntax: icu
+  default: Delivered on {at, date} at {at, time}
+Failed:
+  default: "Sync failed for %(count)d items: %(cause)e"
+`))
+	require.NoError(t, err)
+
+	writeMessages(t, message, "template.golden_var_types")
+}
+
+func TestWriteTypeParamVars(t *testing.T) {
+	for _, raw := range []string{"%(I)d items", "%(F).1f%%", "%(I)s has %(count)d items"} {
+		message, err := staticmessages.Parse("test", strings.NewReader("Items:\n  default: '"+raw+"'\n"))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		err = staticmessages.Write(message, "testpkg", &buf)
+		require.ErrorIs(t, err, staticmessages.ErrReservedKeyword, raw)
+		require.Empty(t, buf.Bytes())
+	}
+
+	// Declared types don't use type parameters.
+	message, err := staticmessages.Parse("test", strings.NewReader("Items:\n  default: '%(I:int64)d items'\n"))
+	require.NoError(t, err)
+	require.NoError(t, staticmessages.Write(message, "testpkg", &bytes.Buffer{}))
+}
+
+func TestWriteTemplateWithDeclaredTypes(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`Transfer:
+  _vars:
+    amount: float64
+    account: net/netip.Addr
+  default: Transferred %(amount).2f to %(account)v in %(count)d parts at %(at:time.Time)t
+  nl: '%(count)d keer %(amount).2f overgemaakt naar %(account)v'
+Invalid:
+  default: 'Invalid document: %(doc:yaml=gopkg.in/yaml.v3.Node)v'
+`))
+	require.NoError(t, err)
+
+	writeMessages(t, message, "template.golden_declared_types")
+}
+
+func TestWriteTemplateWithErrors(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`NotFound:
+  default: User %(ID)d not found
+  nl: Gebruiker %(ID)d niet gevonden
+Failed:
+  default: "Sync of %(ratio).1f failed: %(cause)e, %(rollback)e"
+Denied:
+  default: Access denied
+`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = staticmessages.Write(message, "testpkg", &buf, staticmessages.WithErrors())
+	require.NoError(t, err)
+
+	compareGolden(t, buf.Bytes(), "template.golden_errors")
+}
+
+func TestWriteErrorsNameCollision(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`Save:
+  default: Saved
+SaveError:
+  default: Not saved
+`))
+	require.NoError(t, err)
+
+	// Without the errors the names don't collide.
+	var buf bytes.Buffer
+	require.NoError(t, staticmessages.Write(message, "testpkg", &buf))
+
+	buf.Reset()
+	err = staticmessages.Write(message, "testpkg", &buf, staticmessages.WithErrors())
+	require.ErrorIs(t, err, staticmessages.ErrGeneratedCodeInvalid)
+	require.ErrorContains(t, err, "TestSaveError is declared for message Save of Test and message SaveError of Test")
+	require.Empty(t, buf.Bytes())
+}
+
+func TestWriteErrorsReservedVars(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`Failed:
+  default: "Failed with %(error)s, retried: %(is)b"
+`))
+	require.NoError(t, err)
+
+	// The vars are only fields when the errors are generated.
+	var buf bytes.Buffer
+	require.NoError(t, staticmessages.Write(message, "testpkg", &buf))
+
+	buf.Reset()
+	err = staticmessages.Write(message, "testpkg", &buf, staticmessages.WithErrors())
+	require.ErrorIs(t, err, staticmessages.ErrGeneratedCodeInvalid)
+	require.ErrorIs(t, err, staticmessages.ErrReservedKeyword)
+	require.ErrorContains(t, err, `message Failed: var "error"`)
+	require.Empty(t, buf.Bytes())
+}
+
+func TestWriteTemplateWithLocalizables(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`NotFound:
+  default: User %(ID)d not found at %(at)t
+Denied:
+  default: Access denied
+`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = staticmessages.Write(message, "testpkg", &buf, staticmessages.WithLocalizables())
+	require.NoError(t, err)
+
+	compareGolden(t, buf.Bytes(), "template.golden_localizables")
+}
+
+func TestWriteLocalizablesNameCollision(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`Save:
+  default: Saved
+SaveMsg:
+  default: Saved the message
+`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = staticmessages.Write(message, "testpkg", &buf, staticmessages.WithLocalizables())
+	require.ErrorIs(t, err, staticmessages.ErrGeneratedCodeInvalid)
+	require.ErrorContains(t, err, "TestSaveMsg is declared for message Save of Test and message SaveMsg of Test")
+}
+
+func TestWriteLocalizablesReservedVars(t *testing.T) {
+	for _, name := range []string{"any", "context"} {
+		message, err := staticmessages.Parse("test", strings.NewReader("Saved:\n  default: Saved %("+name+")s\n"))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, staticmessages.Write(message, "testpkg", &buf))
+
+		buf.Reset()
+		err = staticmessages.Write(message, "testpkg", &buf, staticmessages.WithLocalizables())
+		require.ErrorIs(t, err, staticmessages.ErrReservedKeyword, name)
+		require.Empty(t, buf.Bytes())
+	}
+}
+
+func TestWriteTemplateWithCatalog(t *testing.T) {
+	message, err := staticmessages.Parse("test", strings.NewReader(`NotFound:
+  default: User %(ID)d not found
+  nl: Gebruiker %(ID)d niet gevonden
+Transfer:
+  _vars:
+    account: net/netip.Addr
+  default: Transferred %(amount).2f to %(account)v at %(at)t, cause %(cause)e
+Denied:
+  default: Access denied
+`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = staticmessages.Write(message, "testpkg", &buf, staticmessages.WithCatalog())
+	require.NoError(t, err)
+
+	compareGolden(t, buf.Bytes(), "template.golden_catalog")
+}
+
+func TestWritePackage(t *testing.T) {
+	var buf bytes.Buffer
+	users, err := staticmessages.Parse("users", strings.NewReader(`NotFound:
+  default: User %(ID)d not found
+  nl: Gebruiker %(ID)d niet gevonden
+`))
+	require.NoError(t, err)
+
+	orders, err := staticmessages.Parse("orders", strings.NewReader(`Shipped:
+  default: Order shipped
+  pl: Zamówienie wysłane
+  de: Bestellung versandt
+`))
+	require.NoError(t, err)
+
+	err = staticmessages.WritePackage([]*staticmessages.Messages{users, orders}, "testpkg", &buf, staticmessages.WithCatalog())
+	require.NoError(t, err)
+
+	compareGolden(t, buf.Bytes(), "package.golden_catalog")
+}
+
+func TestWritePackageNameCollision(t *testing.T) {
+	users, err := staticmessages.Parse("users", strings.NewReader("AdminSaved:\n  default: Admin saved\n"))
+	require.NoError(t, err)
+
+	admins, err := staticmessages.Parse("usersAdmin", strings.NewReader("Saved:\n  default: Saved\n"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = staticmessages.WritePackage([]*staticmessages.Messages{users, admins}, "testpkg", &buf)
+	require.ErrorIs(t, err, staticmessages.ErrGeneratedCodeInvalid)
+	require.ErrorContains(t, err, "UsersAdminSaved is declared for message AdminSaved of Users and message Saved of UsersAdmin")
+	require.Empty(t, buf.Bytes())
+}
+
+func TestWriteInvalidCode(t *testing.T) {
+	// The parser doesn't create invalid messages like a selector that isn't a Go identifier.
+	selector := &staticmessages.Var{Name: "role)", Type: staticmessages.VarTypeString}
+	message := &staticmessages.Messages{Name: "Test", Messages: []*staticmessages.LocalizedMessage{
+		{Identifier: "Shipped", Default: &staticmessages.Message{Message: "Order shipped"}},
+		{
+			Identifier: "Updated",
+			Default:    &staticmessages.Message{Message: "Updated"},
+			Translations: []*staticmessages.Translation{
+				{Locale: "de", Message: &staticmessages.Message{Message: "Aktualisiert"}},
+				{Locale: "nl", Message: &staticmessages.Message{Selector: selector, Variants: []*staticmessages.Variant{
+					{Key: "other", Message: &staticmessages.Message{Message: "Bijgewerkt"}},
+				}}},
+			},
+		},
+	}}
+
+	var buf bytes.Buffer
+	err := staticmessages.Write(message, "testpkg", &buf)
+	require.ErrorIs(t, err, staticmessages.ErrGeneratedCodeInvalid)
+	require.Empty(t, buf.Bytes())
+
+	require.ErrorContains(t, err, `message Updated, locale nl: var "role)" must contain only letters`)
+
+	// The code of the package is checked as well.
+	require.ErrorIs(t, staticmessages.WritePackage([]*staticmessages.Messages{message}, "test pkg", &buf), staticmessages.ErrGeneratedCodeInvalid)
+	require.Empty(t, buf.Bytes())
+
+	// Invalid code that passes the name checks points at the message that produced it.
+	at := &staticmessages.Var{Name: "at", Type: staticmessages.VarTypeAny, GoType: "dates.Day)"}
+	message.Messages[1].Translations[1].Message = &staticmessages.Message{Message: "Bijgewerkt op %v", Vars: []*staticmessages.Var{at}}
+
+	err = staticmessages.Write(message, "testpkg", &buf)
+	require.ErrorIs(t, err, staticmessages.ErrGeneratedCodeInvalid)
+	require.ErrorContains(t, err, "at dates.Day)")
+	require.Empty(t, buf.Bytes())
+
+	var parseErr *staticmessages.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, "Updated", parseErr.Identifier)
+}
+
 func writeMessages(t *testing.T, message *staticmessages.Messages, goldenFile string) {
 	var buf bytes.Buffer
 	err := staticmessages.Write(message, "testpkg", &buf)
 	require.NoError(t, err)
 
+	compareGolden(t, buf.Bytes(), goldenFile)
+}
+
+// compareGolden compares generated with the golden file, the golden file is written when the gen_golden flag is set.
+func compareGolden(t *testing.T, generated []byte, goldenFile string) {
 	goldenPath := filepath.Join("./testdata/", goldenFile)
 	if *genGolden {
-		err = os.MkdirAll("./testdata", 0755)
+		err := os.MkdirAll("./testdata", 0755)
 		if err != nil {
 			t.Fatalf("Failed to create the testdata directory: %v", err)
 		}
 
-		err = os.WriteFile(goldenPath, buf.Bytes(), 0644)
+		err = os.WriteFile(goldenPath, generated, 0644)
 		if err != nil {
 			t.Fatalf("Failed to write the golden file: %v", err)
 		}
@@ -85,12 +439,12 @@ func writeMessages(t *testing.T, message *staticmessages.Messages, goldenFile st
 	golden, err := os.ReadFile(goldenPath)
 	require.NoError(t, err)
 
-	if !bytes.Equal(golden, buf.Bytes()) {
+	if !bytes.Equal(golden, generated) {
 		t.Log("Golden:")
 		t.Log(string(golden))
 
 		t.Log("Generated:")
-		t.Log(buf.String())
+		t.Log(string(generated))
 		t.Fatalf("Generated file does not match the golden file")
 	}
 }
@@ -0,0 +1,438 @@
+package staticmessages
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrXLIFFInvalid = errors.New("xliff file is invalid")
+
+	// placeholderRe matches the printf placeholders and the simple ICU arguments that are protected as <ph> elements.
+	placeholderRe = regexp.MustCompile(`%\([a-zA-Z]+(?::[^)]+)?\)[0-9\.]*[a-zA-Z]|\{\s*[a-zA-Z]+\s*(?:,\s*(?:number|date|time)\s*(?:,[^{}]*)?)?\}`)
+)
+
+type xliffDocument struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"`
+	TrgLang string      `xml:"trgLang,attr,omitempty"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	ID    string      `xml:"id,attr"`
+	Units []xliffUnit `xml:"unit"`
+}
+
+type xliffUnit struct {
+	ID           string          `xml:"id,attr"`
+	Notes        []xliffNote     `xml:"notes>note,omitempty"`
+	OriginalData []xliffData     `xml:"originalData>data,omitempty"`
+	Segments     []*xliffSegment `xml:"segment"`
+
+	// placeholders is the number of <ph> ids in the unit.
+	placeholders int
+}
+
+type xliffNote struct {
+	Category string `xml:"category,attr,omitempty"`
+	Text     string `xml:",chardata"`
+}
+
+type xliffData struct {
+	ID   string `xml:"id,attr"`
+	Text string `xml:",chardata"`
+}
+
+type xliffSegment struct {
+	ID     string        `xml:"id,attr,omitempty"`
+	Source xliffContent  `xml:"source"`
+	Target *xliffContent `xml:"target"`
+
+	// line is the position of the segment in the file it is read from.
+	line int
+}
+
+func (s *xliffSegment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s.line, _ = d.InputPos()
+
+	// The alias prevents a recursive call to UnmarshalXML.
+	type segment xliffSegment
+	return d.DecodeElement((*segment)(s), &start)
+}
+
+// xliffContent is the content of a source or target, it contains text and <ph> elements.
+type xliffContent struct {
+	Parts []xliffPart
+}
+
+// xliffPart is text or a placeholder that refers to the original data.
+type xliffPart struct {
+	Text    string
+	ID      string
+	DataRef string
+}
+
+func (c *xliffContent) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok := tok.(type) {
+		case xml.CharData:
+			c.Parts = append(c.Parts, xliffPart{Text: string(tok)})
+		case xml.StartElement:
+			if tok.Name.Local != "ph" {
+				return fmt.Errorf("%w: unsupported inline element <%s>, only <ph> is supported", ErrXLIFFInvalid, tok.Name.Local)
+			}
+
+			part := xliffPart{}
+			for _, attr := range tok.Attr {
+				switch attr.Name.Local {
+				case "id":
+					part.ID = attr.Value
+				case "dataRef":
+					part.DataRef = attr.Value
+				}
+			}
+
+			c.Parts = append(c.Parts, part)
+
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// WriteXLIFF writes the messages as an XLIFF 2.0 file with the translations of locale as targets.
+// Every message is a <unit>, plural and select variants are a <segment> in the unit of the message.
+// Placeholders are written as <ph> elements that refer to the placeholder in the <originalData> of the unit.
+func WriteXLIFF(msg *Messages, sourceLocale, locale string, w io.Writer) error {
+	file := xliffFile{ID: msg.Name}
+	var unit *xliffUnit
+	// data contains the data ID of every placeholder in the current unit.
+	var data map[string]string
+
+	for _, u := range msg.Units(locale) {
+		if unit == nil || unit.ID != u.Identifier {
+			file.Units = append(file.Units, xliffUnit{ID: u.Identifier, Notes: xliffNotes(u.Message)})
+			unit = &file.Units[len(file.Units)-1]
+			data = make(map[string]string)
+		}
+
+		segment := &xliffSegment{
+			ID:     xliffSegmentID(u.Keys),
+			Source: unit.content(u.Source, data, nil),
+		}
+
+		if locale != "" {
+			target := unit.content(u.Target, data, segment.Source.Parts)
+			segment.Target = &target
+		}
+
+		unit.Segments = append(unit.Segments, segment)
+	}
+
+	// The file is written by hand, the xml encoder would indent the <ph> elements inside the text.
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="` + xmlEscape(sourceLocale) + `"`)
+	if locale != "" {
+		bw.WriteString(` trgLang="` + xmlEscape(locale) + `"`)
+	}
+	bw.WriteString(">\n")
+	bw.WriteString(`  <file id="` + xmlEscape(file.ID) + "\">\n")
+
+	for _, unit := range file.Units {
+		bw.WriteString(`    <unit id="` + xmlEscape(unit.ID) + "\">\n")
+
+		if len(unit.Notes) > 0 {
+			bw.WriteString("      <notes>\n")
+			for _, note := range unit.Notes {
+				bw.WriteString(`        <note category="` + xmlEscape(note.Category) + `">` + xmlEscape(note.Text) + "</note>\n")
+			}
+			bw.WriteString("      </notes>\n")
+		}
+
+		if len(unit.OriginalData) > 0 {
+			bw.WriteString("      <originalData>\n")
+			for _, data := range unit.OriginalData {
+				bw.WriteString(`        <data id="` + xmlEscape(data.ID) + `">` + xmlEscape(data.Text) + "</data>\n")
+			}
+			bw.WriteString("      </originalData>\n")
+		}
+
+		for _, segment := range unit.Segments {
+			if segment.ID != "" {
+				bw.WriteString(`      <segment id="` + xmlEscape(segment.ID) + "\">\n")
+			} else {
+				bw.WriteString("      <segment>\n")
+			}
+
+			bw.WriteString("        <source>" + segment.Source.xml() + "</source>\n")
+			if segment.Target != nil {
+				bw.WriteString("        <target>" + segment.Target.xml() + "</target>\n")
+			}
+
+			bw.WriteString("      </segment>\n")
+		}
+
+		bw.WriteString("    </unit>\n")
+	}
+
+	bw.WriteString("  </file>\n</xliff>\n")
+
+	return bw.Flush()
+}
+
+// xml returns the content as xml.
+func (c xliffContent) xml() string {
+	var b strings.Builder
+	for _, part := range c.Parts {
+		if part.DataRef == "" {
+			b.WriteString(xmlEscape(part.Text))
+			continue
+		}
+
+		b.WriteString(`<ph id="` + xmlEscape(part.ID) + `" dataRef="` + xmlEscape(part.DataRef) + `"/>`)
+	}
+
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
+
+// content splits text into text and placeholders, new placeholders are added to the original data of u.
+// The placeholders of a target get the id of the same placeholder in the source.
+func (u *xliffUnit) content(text string, data map[string]string, source []xliffPart) xliffContent {
+	c := xliffContent{}
+	offset := 0
+	ids := make(map[string]bool)
+
+	for _, loc := range placeholderRe.FindAllStringIndex(text, -1) {
+		if loc[0] > offset {
+			c.Parts = append(c.Parts, xliffPart{Text: text[offset:loc[0]]})
+		}
+
+		placeholder := text[loc[0]:loc[1]]
+		dataRef, ok := data[placeholder]
+		if !ok {
+			dataRef = "d" + strconv.Itoa(len(data)+1)
+			data[placeholder] = dataRef
+			u.OriginalData = append(u.OriginalData, xliffData{ID: dataRef, Text: placeholder})
+		}
+
+		id := ""
+		for _, part := range source {
+			if part.DataRef == dataRef && !ids[part.ID] {
+				id = part.ID
+				break
+			}
+		}
+
+		if id == "" {
+			u.placeholders++
+			id = "ph" + strconv.Itoa(u.placeholders)
+		}
+
+		ids[id] = true
+		c.Parts = append(c.Parts, xliffPart{ID: id, DataRef: dataRef})
+		offset = loc[1]
+	}
+
+	if offset < len(text) {
+		c.Parts = append(c.Parts, xliffPart{Text: text[offset:]})
+	}
+
+	return c
+}
+
+// xliffSegmentID returns the id of the segment of a variant, the keys are escaped because ids can't contain characters like =.
+func xliffSegmentID(keys []string) string {
+	escaped := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var b strings.Builder
+		for _, c := range []byte(key) {
+			if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "_%02X", c)
+			}
+		}
+
+		escaped = append(escaped, b.String())
+	}
+
+	return strings.Join(escaped, ".")
+}
+
+// xliffKeys returns the variant keys of a segment id written by xliffSegmentID.
+func xliffKeys(id string) ([]string, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	keys := make([]string, 0)
+	for _, escaped := range strings.Split(id, ".") {
+		var b strings.Builder
+		for i := 0; i < len(escaped); i++ {
+			if escaped[i] != '_' {
+				b.WriteByte(escaped[i])
+				continue
+			}
+
+			if i+2 >= len(escaped) {
+				return nil, fmt.Errorf("%w: invalid segment id %q", ErrXLIFFInvalid, id)
+			}
+
+			c, err := strconv.ParseUint(escaped[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid segment id %q", ErrXLIFFInvalid, id)
+			}
+
+			b.WriteByte(byte(c))
+			i += 2
+		}
+
+		keys = append(keys, b.String())
+	}
+
+	return keys, nil
+}
+
+// xliffNotes returns the documentation of a message as notes.
+func xliffNotes(l *LocalizedMessage) []xliffNote {
+	notes := make([]xliffNote, 0, 3)
+	if l.Description != "" {
+		notes = append(notes, xliffNote{Category: "description", Text: l.Description})
+	}
+
+	if l.Context != "" {
+		notes = append(notes, xliffNote{Category: "context", Text: l.Context})
+	}
+
+	if l.Example != "" {
+		notes = append(notes, xliffNote{Category: "example", Text: l.Example})
+	}
+
+	return notes
+}
+
+// ReadXLIFF reads the units of an XLIFF 2.0 file written by WriteXLIFF, the locale is read from the trgLang attribute.
+// The <ph> elements of the targets are replaced with the placeholders in the original data.
+func ReadXLIFF(r io.Reader) (string, []*Unit, error) {
+	return readXLIFF("", r)
+}
+
+// ReadXLIFFFile reads the units of the XLIFF file at path, see ReadXLIFF.
+func ReadXLIFFFile(path string) (string, []*Unit, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	return readXLIFF(path, f)
+}
+
+func readXLIFF(file string, r io.Reader) (string, []*Unit, error) {
+	decoder := xml.NewDecoder(r)
+
+	var doc xliffDocument
+	if err := decoder.Decode(&doc); err != nil {
+		line, _ := decoder.InputPos()
+		if !errors.Is(err, ErrXLIFFInvalid) {
+			err = fmt.Errorf("%w: %v", ErrXLIFFInvalid, err)
+		}
+
+		return "", nil, ParseErrors{{File: file, Line: line, Err: err}}
+	}
+
+	if doc.Version != "2.0" {
+		return "", nil, ParseErrors{{File: file, Line: 1, Err: fmt.Errorf("%w: expected version 2.0 got %q", ErrXLIFFInvalid, doc.Version)}}
+	}
+
+	var errs ParseErrors
+	units := make([]*Unit, 0)
+
+	for _, f := range doc.Files {
+		for _, u := range f.Units {
+			data := make(map[string]string)
+			for _, d := range u.OriginalData {
+				data[d.ID] = d.Text
+			}
+
+			for _, segment := range u.Segments {
+				keys, err := xliffKeys(segment.ID)
+				if err != nil {
+					errs = append(errs, &ParseError{File: file, Line: segment.line, Identifier: u.ID, Locale: doc.TrgLang, Err: err})
+					continue
+				}
+
+				unit := &Unit{
+					ID:         unitID(u.ID, keys),
+					Identifier: u.ID,
+					Keys:       keys,
+					File:       file,
+					Line:       segment.line,
+				}
+
+				unit.Source, err = segment.Source.text(data)
+				if err == nil && segment.Target != nil {
+					unit.Target, err = segment.Target.text(data)
+				}
+
+				if err != nil {
+					errs = append(errs, &ParseError{File: file, Line: segment.line, Identifier: u.ID, Locale: doc.TrgLang, Err: err})
+					continue
+				}
+
+				units = append(units, unit)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return "", nil, errs
+	}
+
+	return doc.TrgLang, units, nil
+}
+
+// text returns the content with the placeholders from the original data.
+func (c xliffContent) text(data map[string]string) (string, error) {
+	var b strings.Builder
+	for _, part := range c.Parts {
+		if part.DataRef == "" {
+			b.WriteString(part.Text)
+			continue
+		}
+
+		placeholder, ok := data[part.DataRef]
+		if !ok {
+			return "", fmt.Errorf("%w: <ph> refers to unknown data %q", ErrXLIFFInvalid, part.DataRef)
+		}
+
+		b.WriteString(placeholder)
+	}
+
+	return b.String(), nil
+}
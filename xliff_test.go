@@ -0,0 +1,97 @@
+package staticmessages_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wvell/staticmessages"
+)
+
+func TestWriteXLIFF(t *testing.T) {
+	messages, err := staticmessages.Parse("users", strings.NewReader(poSource))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, staticmessages.WriteXLIFF(messages, "en", "nl", &buf))
+
+	compareGolden(t, buf.Bytes(), "xliff.golden_nl")
+}
+
+func TestReadXLIFF(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		messages, err := staticmessages.Parse("users", strings.NewReader(poSource))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, staticmessages.WriteXLIFF(messages, "en", "nl", &buf))
+
+		locale, units, err := staticmessages.ReadXLIFF(&buf)
+		require.NoError(t, err)
+		require.Equal(t, "nl", locale)
+
+		expected := messages.Units("nl")
+		require.Len(t, units, len(expected))
+		for i, unit := range units {
+			require.Equal(t, expected[i].ID, unit.ID)
+			require.Equal(t, expected[i].Keys, unit.Keys)
+			require.Equal(t, expected[i].Source, unit.Source)
+			require.Equal(t, expected[i].Target, unit.Target)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		locale, units, err := staticmessages.ReadXLIFF(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="de">
+  <file id="Users">
+    <unit id="NotFound">
+      <originalData>
+        <data id="d1">%(ID)d</data>
+      </originalData>
+      <segment>
+        <source>User <ph id="ph1" dataRef="d1"/> not found</source>
+        <target>Benutzer <ph id="ph1" dataRef="d1"/> nicht gefunden</target>
+      </segment>
+    </unit>
+    <unit id="Files">
+      <originalData>
+        <data id="d1">%(folder)s</data>
+        <data id="d2">%(count)d</data>
+      </originalData>
+      <segment id="_3D0">
+        <source>No files in <ph id="ph1" dataRef="d1"/></source>
+        <target>Keine Dateien in <ph id="ph1" dataRef="d1"/></target>
+      </segment>
+      <segment id="other">
+        <source><ph id="ph3" dataRef="d2"/> files in <ph id="ph4" dataRef="d1"/></source>
+        <target><ph id="ph3" dataRef="d2"/> Dateien in <ph id="ph4" dataRef="d1"/></target>
+      </segment>
+    </unit>
+  </file>
+</xliff>`))
+		require.NoError(t, err)
+		require.Equal(t, "de", locale)
+		require.Equal(t, []string{"=0"}, units[1].Keys)
+
+		updated, err := staticmessages.ImportUnits("users.yml", []byte(poSource), locale, units)
+		require.NoError(t, err)
+
+		messages, err := staticmessages.Parse("users", bytes.NewReader(updated))
+		require.NoError(t, err)
+		require.Equal(t, "Benutzer %(ID)d nicht gefunden", messages.Messages[0].Translation("de").Raw)
+		require.Equal(t, "%d Dateien in %s", messages.Messages[1].Translation("de").OtherVariant().Message.Message)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, raw := range []string{
+			`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="1.2"></xliff>`,
+			`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0"><file><unit id="A"><segment><source>A</source><target><pc id="1">A</pc></target></segment></unit></file></xliff>`,
+			`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0"><file><unit id="A"><segment><source>A</source><target><ph id="1" dataRef="d9"/></target></segment></unit></file></xliff>`,
+			`<xliff version="2.0"><file>`,
+		} {
+			_, _, err := staticmessages.ReadXLIFF(strings.NewReader(raw))
+			require.ErrorIs(t, err, staticmessages.ErrXLIFFInvalid, raw)
+		}
+	})
+}